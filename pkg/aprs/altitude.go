@@ -0,0 +1,47 @@
+// GoBalloon
+// altitude.go - GPS/barometric altitude fusion for position reports
+//
+// (c) 2018, Christopher Snell
+
+package aprs
+
+import (
+	"sort"
+
+	"github.com/chrissnell/GoBalloon/pkg/geospatial"
+)
+
+// AltitudePolicy selects how CreateCompressedPositionReport resolves a
+// Point's reported altitude when both a GPS fix and a barometric reading
+// are available.
+type AltitudePolicy int
+
+// Fusion policies. GPS altitude is known to glitch near 60 kft and
+// above, so flights that carry a barometer will often prefer it, or a
+// median of the two, over raw GPS.
+const (
+	AltitudePreferGPS AltitudePolicy = iota
+	AltitudePreferBaro
+	AltitudeMedian
+)
+
+// resolveAltitude applies policy to p, returning the altitude (in feet)
+// that should be encoded into the position report and the source that
+// produced it. p.PressureAltitude of zero is treated as "no barometer
+// reading available," falling back to GPS.
+func resolveAltitude(p geospatial.Point, policy AltitudePolicy) (float64, geospatial.AltitudeSource) {
+	if p.PressureAltitude == 0 {
+		return p.Altitude, geospatial.AltitudeSourceGPS
+	}
+
+	switch policy {
+	case AltitudePreferBaro:
+		return p.PressureAltitude, geospatial.AltitudeSourceBaro
+	case AltitudeMedian:
+		values := []float64{p.Altitude, p.PressureAltitude}
+		sort.Float64s(values)
+		return (values[0] + values[1]) / 2, geospatial.AltitudeSourceFused
+	default:
+		return p.Altitude, geospatial.AltitudeSourceGPS
+	}
+}
@@ -0,0 +1,50 @@
+// GoBalloon
+// altitude_test.go - tests for GPS/barometric altitude fusion
+//
+// (c) 2026, GoBalloon contributors
+
+package aprs
+
+import (
+	"testing"
+
+	"github.com/chrissnell/GoBalloon/pkg/geospatial"
+)
+
+func TestResolveAltitudeFallsBackToGPSWithoutBaro(t *testing.T) {
+	p := geospatial.Point{Altitude: 1000, PressureAltitude: 0}
+
+	for _, policy := range []AltitudePolicy{AltitudePreferGPS, AltitudePreferBaro, AltitudeMedian} {
+		alt, src := resolveAltitude(p, policy)
+		if alt != 1000 || src != geospatial.AltitudeSourceGPS {
+			t.Errorf("resolveAltitude(policy=%v) = (%v, %v), want (1000, AltitudeSourceGPS)", policy, alt, src)
+		}
+	}
+}
+
+func TestResolveAltitudePreferGPS(t *testing.T) {
+	p := geospatial.Point{Altitude: 1000, PressureAltitude: 1100}
+
+	alt, src := resolveAltitude(p, AltitudePreferGPS)
+	if alt != 1000 || src != geospatial.AltitudeSourceGPS {
+		t.Errorf("resolveAltitude(AltitudePreferGPS) = (%v, %v), want (1000, AltitudeSourceGPS)", alt, src)
+	}
+}
+
+func TestResolveAltitudePreferBaro(t *testing.T) {
+	p := geospatial.Point{Altitude: 1000, PressureAltitude: 1100}
+
+	alt, src := resolveAltitude(p, AltitudePreferBaro)
+	if alt != 1100 || src != geospatial.AltitudeSourceBaro {
+		t.Errorf("resolveAltitude(AltitudePreferBaro) = (%v, %v), want (1100, AltitudeSourceBaro)", alt, src)
+	}
+}
+
+func TestResolveAltitudeMedian(t *testing.T) {
+	p := geospatial.Point{Altitude: 1000, PressureAltitude: 1100}
+
+	alt, src := resolveAltitude(p, AltitudeMedian)
+	if alt != 1050 || src != geospatial.AltitudeSourceFused {
+		t.Errorf("resolveAltitude(AltitudeMedian) = (%v, %v), want (1050, AltitudeSourceFused)", alt, src)
+	}
+}
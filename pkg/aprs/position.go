@@ -19,7 +19,9 @@ import (
 
 // CreateUncompressedPositionReportWithoutTimestamp creates an APRS position report without a timestamp.
 // The report is in a format suitable for adding to the data payload of an AX.25 APRS packet.
-func CreateUncompressedPositionReportWithoutTimestamp(p geospatial.Point, symTable, symCode rune, messaging bool) (string, error) {
+// If comment is non-empty, it is appended to the report as free text, e.g. a GNSS fix-quality
+// summary produced by gnss.Tracker.Comment.
+func CreateUncompressedPositionReportWithoutTimestamp(p geospatial.Point, symTable, symCode rune, messaging bool, comment string) (string, error) {
 	var buffer bytes.Buffer
 	var latitudeHemisphere, longitudeHemisphere rune
 
@@ -59,14 +61,23 @@ func CreateUncompressedPositionReportWithoutTimestamp(p geospatial.Point, symTab
 
 	buffer.WriteRune(symCode)
 
+	if comment != "" {
+		buffer.WriteString(comment)
+	}
+
 	return buffer.String(), nil
 }
 
 // CreateCompressedPositionReport  creates an APRS position report in compressed format.
 // The report is in a format suitable for adding to the data payload of an AX.25 APRS packet.
-func CreateCompressedPositionReport(p geospatial.Point, symTable, symCode rune) string {
+// If comment is non-empty, it is appended to the report as free text, e.g. a GNSS fix-quality
+// summary produced by gnss.Tracker.Comment. policy selects which of p.Altitude (GPS) and
+// p.PressureAltitude (barometric) is encoded as the report's altitude.
+func CreateCompressedPositionReport(p geospatial.Point, symTable, symCode rune, comment string, policy AltitudePolicy) string {
 	var buffer bytes.Buffer
 
+	altitude, _ := resolveAltitude(p, policy)
+
 	// First byte in our compressed position report is the data type indicator.
 	// The rune '!' indicates a real-time compressed position report
 	buffer.WriteRune('!')
@@ -84,13 +95,17 @@ func CreateCompressedPositionReport(p geospatial.Point, symTable, symCode rune)
 	buffer.WriteRune(symCode)
 
 	// Then we compress our altitude with a funky logrithm and conver to Base91
-	buffer.Write(base91.AltitudeCompress(p.Altitude))
+	buffer.Write(base91.AltitudeCompress(altitude))
 
 	// This last byte specifies: a live GPS fix, in GGA NMEA format, with the
 	// compressed position generated by software (this program!).  See APRS
 	// Protocol Reference v1.0, page 39, for more details on this wack shit.
 	buffer.WriteByte(byte(0x32) + 33)
 
+	if comment != "" {
+		buffer.WriteString(comment)
+	}
+
 	return buffer.String()
 }
 
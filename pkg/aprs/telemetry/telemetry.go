@@ -0,0 +1,159 @@
+// GoBalloon
+// telemetry.go - APRS telemetry reports (T#... data and PARM/UNIT/EQNS/BITS metadata)
+//
+// (c) 2018, Christopher Snell
+
+// Package telemetry builds the APRS telemetry packets defined in the APRS
+// Protocol Reference, chapter 13: the "T#nnn,a1,a2,a3,a4,a5,bbbbbbbb" data
+// packet, and the PARM/UNIT/EQNS/BITS messages that tell a receiving
+// station what the five analog channels and eight digital bits mean.
+package telemetry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	maxChannels = 5
+	maxBits     = 8
+	maxSequence = 1000 // sequence numbers run 000-999
+)
+
+// channel holds one analog telemetry channel's metadata: its name (for
+// PARM), its unit (for UNIT), and the linear equation coefficients
+// (a, b, c in a*n^2 + b*n + c) that convert the raw 0-255 value back to
+// an engineering unit (for EQNS).
+type channel struct {
+	name   string
+	unit   string
+	coeffs [3]float64
+}
+
+// TelemetryEncoder builds T# data packets for up to five analog channels
+// and eight digital bits, addressed to a single station callsign, along
+// with the PARM/UNIT/EQNS/BITS metadata packets that a receiving station
+// needs in order to decode them.
+type TelemetryEncoder struct {
+	station string // the callsign telemetry is reported "for"
+
+	channels []channel
+	bitNames [maxBits]string
+
+	// metadataInterval is how many ShouldSendMetadata calls occur between
+	// ones that report metadata as due; 0 disables the rate limiting.
+	metadataInterval int
+	sinceMetadata    int
+}
+
+// NewTelemetryEncoder returns an encoder reporting telemetry on behalf of
+// station (its packets are APRS messages addressed to that callsign).
+// ShouldSendMetadata reports metadata as due once every metadataInterval
+// data packets; the encoder never sends it on its own. Pass 0 to turn
+// that off and schedule Metadata yourself.
+func NewTelemetryEncoder(station string, metadataInterval int) *TelemetryEncoder {
+	return &TelemetryEncoder{
+		station:          station,
+		metadataInterval: metadataInterval,
+	}
+}
+
+// AddChannel registers an analog telemetry channel. Channels are encoded
+// in the order they were added, so callers must add them in the same
+// order the sensor values will later be passed to Encode. At most five
+// channels are supported, matching the APRS telemetry format.
+func (e *TelemetryEncoder) AddChannel(name, unit string, coeffs [3]float64) error {
+	if len(e.channels) >= maxChannels {
+		return fmt.Errorf("telemetry: cannot add channel %q, already have %d channels", name, maxChannels)
+	}
+	e.channels = append(e.channels, channel{name: name, unit: unit, coeffs: coeffs})
+	return nil
+}
+
+// SetBitName labels one of the eight digital status bits (0-7) for the
+// BITS metadata packet, e.g. SetBitName(0, "cutdown armed").
+func (e *TelemetryEncoder) SetBitName(index int, name string) error {
+	if index < 0 || index >= maxBits {
+		return fmt.Errorf("telemetry: bit index %d out of range 0-%d", index, maxBits-1)
+	}
+	e.bitNames[index] = name
+	return nil
+}
+
+// NextSequence returns seq+1, wrapping from 999 back to 0 as required by
+// the APRS telemetry sequence counter.
+func NextSequence(seq int) int {
+	return (seq + 1) % maxSequence
+}
+
+// Encode renders one T# telemetry data packet. values must have exactly
+// as many entries as channels have been added via AddChannel, each in
+// the raw 0-255 range the EQNS coefficients expect. bits is packed MSB
+// first: bit 7 of the byte is channel 1, matching the APRS convention.
+func (e *TelemetryEncoder) Encode(seq int, values []float64, bits uint8) (string, error) {
+	if seq < 0 || seq >= maxSequence {
+		return "", fmt.Errorf("telemetry: sequence %d out of range 0-%d", seq, maxSequence-1)
+	}
+	if len(values) != len(e.channels) {
+		return "", fmt.Errorf("telemetry: got %d values, expected %d channels", len(values), len(e.channels))
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "T#%03d", seq)
+	for _, v := range values {
+		fmt.Fprintf(&buf, ",%03.0f", v)
+	}
+
+	buf.WriteByte(',')
+	for i := maxBits - 1; i >= 0; i-- {
+		if bits&(1<<uint(i)) != 0 {
+			buf.WriteByte('1')
+		} else {
+			buf.WriteByte('0')
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// ShouldSendMetadata reports whether the next call to Encode should be
+// preceded by a re-transmission of the PARM/UNIT/EQNS/BITS packets,
+// rate-limiting them to once every metadataInterval data packets. Callers
+// that manage their own schedule can ignore this and call Metadata
+// directly.
+func (e *TelemetryEncoder) ShouldSendMetadata() bool {
+	if e.metadataInterval <= 0 {
+		return false
+	}
+	due := e.sinceMetadata == 0
+	e.sinceMetadata = (e.sinceMetadata + 1) % e.metadataInterval
+	return due
+}
+
+// Metadata renders the PARM, UNIT, EQNS, and BITS messages describing
+// the channels and bits registered so far, in the order a receiving
+// station expects to see them.
+func (e *TelemetryEncoder) Metadata() []string {
+	names := make([]string, len(e.channels))
+	units := make([]string, len(e.channels))
+	eqns := make([]string, 0, len(e.channels)*3)
+	for i, c := range e.channels {
+		names[i] = c.name
+		units[i] = c.unit
+		eqns = append(eqns, fmt.Sprintf("%g", c.coeffs[0]), fmt.Sprintf("%g", c.coeffs[1]), fmt.Sprintf("%g", c.coeffs[2]))
+	}
+
+	bits := make([]string, 0, maxBits)
+	for _, n := range e.bitNames {
+		bits = append(bits, n)
+	}
+
+	addr := fmt.Sprintf("%-9s", e.station)
+
+	return []string{
+		fmt.Sprintf(":%s:PARM.%s", addr, strings.Join(names, ",")),
+		fmt.Sprintf(":%s:UNIT.%s", addr, strings.Join(units, ",")),
+		fmt.Sprintf(":%s:EQNS.%s", addr, strings.Join(eqns, ",")),
+		fmt.Sprintf(":%s:BITS.%s", addr, strings.Join(bits, ",")),
+	}
+}
@@ -0,0 +1,128 @@
+// GoBalloon
+// telemetry_test.go - tests for APRS telemetry encoding and metadata
+//
+// (c) 2026, GoBalloon contributors
+
+package telemetry
+
+import "testing"
+
+func TestEncodeRendersDataPacket(t *testing.T) {
+	e := NewTelemetryEncoder("KD2ABC-11", 0)
+	if err := e.AddChannel("temp", "C", [3]float64{0, 1, 0}); err != nil {
+		t.Fatalf("AddChannel: %v", err)
+	}
+	if err := e.AddChannel("pressure", "hPa", [3]float64{0, 1, 0}); err != nil {
+		t.Fatalf("AddChannel: %v", err)
+	}
+
+	got, err := e.Encode(7, []float64{21, 1013}, 0x80)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	const want = "T#007,021,1013,10000000"
+	if got != want {
+		t.Errorf("Encode() = %q, want %q", got, want)
+	}
+}
+
+func TestEncodeRejectsMismatchedValueCount(t *testing.T) {
+	e := NewTelemetryEncoder("KD2ABC-11", 0)
+	if err := e.AddChannel("temp", "C", [3]float64{0, 1, 0}); err != nil {
+		t.Fatalf("AddChannel: %v", err)
+	}
+
+	if _, err := e.Encode(0, []float64{1, 2}, 0); err == nil {
+		t.Error("Encode with 2 values for 1 channel: want error, got nil")
+	}
+}
+
+func TestEncodeRejectsOutOfRangeSequence(t *testing.T) {
+	e := NewTelemetryEncoder("KD2ABC-11", 0)
+
+	if _, err := e.Encode(1000, nil, 0); err == nil {
+		t.Error("Encode with sequence 1000: want error, got nil")
+	}
+	if _, err := e.Encode(-1, nil, 0); err == nil {
+		t.Error("Encode with sequence -1: want error, got nil")
+	}
+}
+
+func TestAddChannelRejectsMoreThanFive(t *testing.T) {
+	e := NewTelemetryEncoder("KD2ABC-11", 0)
+	for i := 0; i < maxChannels; i++ {
+		if err := e.AddChannel("ch", "u", [3]float64{}); err != nil {
+			t.Fatalf("AddChannel %d: %v", i, err)
+		}
+	}
+	if err := e.AddChannel("overflow", "u", [3]float64{}); err == nil {
+		t.Error("AddChannel past the 5-channel limit: want error, got nil")
+	}
+}
+
+func TestNextSequenceWrapsAt999(t *testing.T) {
+	if got := NextSequence(999); got != 0 {
+		t.Errorf("NextSequence(999) = %d, want 0", got)
+	}
+	if got := NextSequence(5); got != 6 {
+		t.Errorf("NextSequence(5) = %d, want 6", got)
+	}
+}
+
+func TestMetadataOrdersPARMUnitEQNSBits(t *testing.T) {
+	e := NewTelemetryEncoder("KD2ABC-11", 0)
+	if err := e.AddChannel("temp", "C", [3]float64{0, 1, 0}); err != nil {
+		t.Fatalf("AddChannel: %v", err)
+	}
+	if err := e.SetBitName(0, "cutdown armed"); err != nil {
+		t.Fatalf("SetBitName: %v", err)
+	}
+
+	got := e.Metadata()
+	if len(got) != 4 {
+		t.Fatalf("len(Metadata()) = %d, want 4", len(got))
+	}
+
+	const wantParm = ":KD2ABC-11:PARM.temp"
+	const wantUnit = ":KD2ABC-11:UNIT.C"
+	const wantEqns = ":KD2ABC-11:EQNS.0,1,0"
+	if got[0] != wantParm {
+		t.Errorf("Metadata()[0] = %q, want %q", got[0], wantParm)
+	}
+	if got[1] != wantUnit {
+		t.Errorf("Metadata()[1] = %q, want %q", got[1], wantUnit)
+	}
+	if got[2] != wantEqns {
+		t.Errorf("Metadata()[2] = %q, want %q", got[2], wantEqns)
+	}
+	const wantBits = ":KD2ABC-11:BITS.cutdown armed,,,,,,,"
+	if got[3] != wantBits {
+		t.Errorf("Metadata()[3] = %q, want %q", got[3], wantBits)
+	}
+}
+
+func TestShouldSendMetadataRateLimits(t *testing.T) {
+	e := NewTelemetryEncoder("KD2ABC-11", 3)
+
+	got := []bool{}
+	for i := 0; i < 6; i++ {
+		got = append(got, e.ShouldSendMetadata())
+	}
+
+	want := []bool{true, false, false, true, false, false}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ShouldSendMetadata() call %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestShouldSendMetadataDisabledWhenIntervalZero(t *testing.T) {
+	e := NewTelemetryEncoder("KD2ABC-11", 0)
+	for i := 0; i < 3; i++ {
+		if e.ShouldSendMetadata() {
+			t.Errorf("ShouldSendMetadata() call %d = true, want false with metadataInterval=0", i)
+		}
+	}
+}
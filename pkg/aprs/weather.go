@@ -0,0 +1,36 @@
+// GoBalloon
+// weather.go - APRS weather-style telemetry extensions for altitude logging
+//
+// (c) 2018, Christopher Snell
+
+package aprs
+
+import (
+	"fmt"
+
+	"github.com/chrissnell/GoBalloon/pkg/geospatial"
+)
+
+// CreateAltitudeWeatherExtension builds a small weather-style comment
+// extension carrying the barometer's temperature reading ("t", in whole
+// degrees Fahrenheit, per the APRS weather format) alongside a
+// GoBalloon-specific "T" field carrying barometric altitude in feet.
+// Appending this to a position report whose encoded altitude is GPS
+// altitude lets a ground station log GPS and barometric altitude
+// independently and reconstruct the flight profile after recovery. The
+// extension does not carry GPS altitude, so when the fusion policy
+// encoded barometric or fused altitude, GPS altitude is in neither.
+func CreateAltitudeWeatherExtension(p geospatial.Point) string {
+	if p.PressureAltitude == 0 {
+		return ""
+	}
+
+	tempF := int(p.Temperature*9/5 + 32)
+	if tempF < -99 {
+		tempF = -99
+	} else if tempF > 999 {
+		tempF = 999
+	}
+
+	return fmt.Sprintf("t%03dT%06.0f", tempF, p.PressureAltitude)
+}
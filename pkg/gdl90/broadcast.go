@@ -0,0 +1,56 @@
+// GoBalloon
+// broadcast.go - UDP transport for GDL90 messages
+//
+// (c) 2018, Christopher Snell
+
+package gdl90
+
+import (
+	"fmt"
+	"net"
+)
+
+// DefaultPort is the UDP port that ForeFlight, Avare, and other portable
+// EFB apps listen on for GDL90 traffic by default.
+const DefaultPort = 4000
+
+// Broadcaster sends framed GDL90 messages over UDP to a single
+// destination, typically the broadcast or unicast address of the
+// network that the EFB devices are on.
+type Broadcaster struct {
+	conn *net.UDPConn
+}
+
+// NewBroadcaster resolves dest (host:port, or just host to use
+// DefaultPort) and returns a Broadcaster ready to Send messages to it.
+func NewBroadcaster(dest string) (*Broadcaster, error) {
+	addr, err := net.ResolveUDPAddr("udp", withDefaultPort(dest))
+	if err != nil {
+		return nil, fmt.Errorf("gdl90: resolving %q: %v", dest, err)
+	}
+
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return nil, fmt.Errorf("gdl90: dialing %q: %v", dest, err)
+	}
+
+	return &Broadcaster{conn: conn}, nil
+}
+
+// Send frames msg and writes it to the configured destination.
+func (b *Broadcaster) Send(msg []byte) error {
+	_, err := b.conn.Write(Frame(msg))
+	return err
+}
+
+// Close releases the underlying UDP socket.
+func (b *Broadcaster) Close() error {
+	return b.conn.Close()
+}
+
+func withDefaultPort(dest string) string {
+	if _, _, err := net.SplitHostPort(dest); err == nil {
+		return dest
+	}
+	return fmt.Sprintf("%s:%d", dest, DefaultPort)
+}
@@ -0,0 +1,95 @@
+// GoBalloon
+// gdl90.go - GDL90 message framing (CRC, byte-stuffing) for ADS-B EFB traffic display
+//
+// (c) 2018, Christopher Snell
+
+// Package gdl90 builds GDL90 messages (Heartbeat, Ownship, and Traffic
+// Report) from decoded APRS position reports so that a Stratux-style
+// receiver on the ground can rebroadcast a tracked balloon to nearby
+// ForeFlight/Avare EFB devices as ADS-B-style traffic. Balloons crossing
+// controlled airspace are a hazard to GA traffic that doesn't otherwise
+// know they're there.
+package gdl90
+
+import (
+	"bytes"
+	"hash/fnv"
+)
+
+// Message IDs used by the GDL90 messages GoBalloon emits.
+const (
+	MsgIDHeartbeat     = 0x00
+	MsgIDOwnship       = 0x0A
+	MsgIDTrafficReport = 0x14
+)
+
+const (
+	flagByte   = 0x7E
+	escapeByte = 0x7D
+	escapeXOR  = 0x20
+)
+
+// crc16Table is the CRC-16-CCITT table GDL90 specifies for its trailing
+// checksum (polynomial 0x1021, initial value 0).
+var crc16Table [256]uint16
+
+func init() {
+	for i := 0; i < 256; i++ {
+		crc := uint16(i) << 8
+		for b := 0; b < 8; b++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+		crc16Table[i] = crc
+	}
+}
+
+// crc16 computes the GDL90 CRC-16-CCITT over msg.
+func crc16(msg []byte) uint16 {
+	var crc uint16
+	for _, b := range msg {
+		crc = (crc << 8) ^ crc16Table[byte(crc>>8)^b]
+	}
+	return crc
+}
+
+// Frame appends msg's CRC-16-CCITT, byte-stuffs any 0x7D/0x7E bytes in
+// the payload+CRC, and wraps the result in the 0x7E flag bytes GDL90
+// uses to delimit messages on the wire.
+func Frame(msg []byte) []byte {
+	crc := crc16(msg)
+
+	var body bytes.Buffer
+	body.Write(msg)
+	body.WriteByte(byte(crc))
+	body.WriteByte(byte(crc >> 8))
+
+	var out bytes.Buffer
+	out.WriteByte(flagByte)
+	for _, b := range body.Bytes() {
+		if b == flagByte || b == escapeByte {
+			out.WriteByte(escapeByte)
+			out.WriteByte(b ^ escapeXOR)
+		} else {
+			out.WriteByte(b)
+		}
+	}
+	out.WriteByte(flagByte)
+
+	return out.Bytes()
+}
+
+// ICAOAddressFromCallsign synthesizes a 24-bit pseudo-ICAO address from
+// an APRS callsign and SSID. Real GDL90 traffic should carry its actual
+// ICAO address, but a rebroadcast balloon has none, so we derive a
+// stable one and mark it as non-ICAO in the traffic report's address
+// type field.
+func ICAOAddressFromCallsign(callsign string, ssid uint8) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(callsign))
+	h.Write([]byte{ssid})
+	return h.Sum32() & 0x00FFFFFF
+}
@@ -0,0 +1,115 @@
+// GoBalloon
+// gdl90_test.go - Tests for GDL90 framing and message encoding
+//
+// (c) 2018, Christopher Snell
+
+package gdl90
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chrissnell/GoBalloon/pkg/geospatial"
+)
+
+func TestFrameEscapesFlagAndEscapeBytes(t *testing.T) {
+	msg := []byte{0x00, 0x7E, 0x7D, 0x01}
+	framed := Frame(msg)
+
+	if framed[0] != flagByte || framed[len(framed)-1] != flagByte {
+		t.Fatalf("Frame() = %x, want leading/trailing flag bytes", framed)
+	}
+
+	body := framed[1 : len(framed)-1]
+	for i := 0; i < len(body); i++ {
+		if body[i] == flagByte {
+			t.Fatalf("Frame() left an unescaped flag byte in the body: %x", framed)
+		}
+		if body[i] == escapeByte {
+			if i+1 >= len(body) {
+				t.Fatalf("Frame() ended on a dangling escape byte: %x", framed)
+			}
+			i++ // skip the escaped byte
+		}
+	}
+}
+
+func TestFrameAppendsVerifiableCRC(t *testing.T) {
+	msg := []byte{0x00, 0x81, 0x41, 0xDB, 0xD0, 0x08, 0x02}
+	framed := Frame(msg)
+
+	// Undo the byte-stuffing to recover msg+CRC, then recompute the CRC
+	// over just msg and check it matches what Frame appended.
+	body := framed[1 : len(framed)-1]
+	var unstuffed []byte
+	for i := 0; i < len(body); i++ {
+		b := body[i]
+		if b == escapeByte {
+			i++
+			b = body[i] ^ escapeXOR
+		}
+		unstuffed = append(unstuffed, b)
+	}
+
+	if len(unstuffed) != len(msg)+2 {
+		t.Fatalf("unstuffed length = %d, want %d (msg + 2 CRC bytes)", len(unstuffed), len(msg)+2)
+	}
+
+	gotCRC := uint16(unstuffed[len(msg)]) | uint16(unstuffed[len(msg)+1])<<8
+	wantCRC := crc16(msg)
+	if gotCRC != wantCRC {
+		t.Errorf("Frame() appended CRC %#04x, want %#04x", gotCRC, wantCRC)
+	}
+}
+
+func TestHeartbeatCarriesTimestampBit16(t *testing.T) {
+	midnight := time.Date(2026, 7, 27, 0, 0, 0, 0, time.UTC)
+
+	// A time-of-day under 65,536s: bit 16 must be clear.
+	early := midnight.Add(1000 * time.Second)
+	msg := Heartbeat(early, true, 0)
+	if msg[2]&0x80 != 0 {
+		t.Errorf("Heartbeat(%v) status byte 2 = %#02x, want bit 7 clear", early, msg[2])
+	}
+	secs := uint32(msg[3]) | uint32(msg[4])<<8
+	if secs != 1000 {
+		t.Errorf("Heartbeat(%v) low 16 bits = %d, want 1000", early, secs)
+	}
+
+	// A time-of-day past 18:12:15 UTC (65,535s after midnight): bit 16
+	// must be set, and the low 16 bits must wrap rather than overflow.
+	late := midnight.Add(70000 * time.Second)
+	msg = Heartbeat(late, true, 0)
+	if msg[2]&0x80 == 0 {
+		t.Errorf("Heartbeat(%v) status byte 2 = %#02x, want bit 7 set", late, msg[2])
+	}
+	secs = uint32(msg[3]) | uint32(msg[4])<<8
+	if secs != 70000-65536 {
+		t.Errorf("Heartbeat(%v) low 16 bits = %d, want %d", late, secs, 70000-65536)
+	}
+}
+
+func TestTrafficMessageVelocityIsNoData(t *testing.T) {
+	msg := trafficMessage(MsgIDOwnship, 0xABCDEF, geospatial.Point{}, "N0CALL")
+
+	// Horizontal velocity (msg[14] + high nibble of msg[15]) is the
+	// all-ones "unavailable" sentinel, 0xFFF.
+	hVel := uint16(msg[14])<<4 | uint16(msg[15])>>4
+	if hVel != 0xFFF {
+		t.Errorf("horizontal velocity = %#03x, want 0xFFF (unavailable)", hVel)
+	}
+
+	// Vertical velocity (low nibble of msg[15] + msg[16]) is the "no
+	// data" sentinel, 0x800, not a large positive climb rate.
+	vVel := uint16(msg[15]&0x0F)<<8 | uint16(msg[16])
+	if vVel != 0x800 {
+		t.Errorf("vertical velocity = %#03x, want 0x800 (no data)", vVel)
+	}
+}
+
+func TestTrafficMessageEmitterCategoryIsLighterThanAir(t *testing.T) {
+	msg := trafficMessage(MsgIDTrafficReport, 0x010203, geospatial.Point{}, "N0CALL")
+	if msg[18] != 10 {
+		t.Errorf("emitter category = %d, want 10 (Lighter-than-air)", msg[18])
+	}
+}
@@ -0,0 +1,169 @@
+// GoBalloon
+// messages.go - GDL90 Heartbeat, Ownship, and Traffic Report message builders
+//
+// (c) 2018, Christopher Snell
+
+package gdl90
+
+import (
+	"strings"
+	"time"
+
+	"github.com/chrissnell/GoBalloon/pkg/geospatial"
+)
+
+// Emitter categories, GDL90 table 11. GoBalloon reports balloons as
+// "Lighter-than-air."
+const EmitterCategoryBalloon = 10
+
+// NIC/NACp values claimed for rebroadcast balloon traffic. These are
+// deliberately conservative: we're relaying a decoded APRS fix, not a
+// certified ADS-B source, so we don't want an EFB trusting the position
+// more than it should.
+const (
+	defaultNIC  = 8 // <= 0.1nm, typical of a good GPS fix
+	defaultNACp = 8
+)
+
+// encodeLatLon converts a signed latitude or longitude in decimal
+// degrees to GDL90's 24-bit two's-complement representation, with a
+// resolution of 180/2^23 degrees per count.
+func encodeLatLon(deg float64) uint32 {
+	const resolution = 180.0 / (1 << 23)
+	v := int32(deg / resolution)
+	return uint32(v) & 0x00FFFFFF
+}
+
+// encodeAltitude converts an altitude in feet to GDL90's 12-bit field:
+// 25-foot resolution, offset so that -1,000ft reads as 0.
+func encodeAltitude(feet float64) uint16 {
+	v := int32((feet + 1000) / 25)
+	if v < 0 {
+		v = 0
+	}
+	if v > 0xFFE {
+		v = 0xFFE // 0xFFF is reserved to mean "altitude invalid"
+	}
+	return uint16(v)
+}
+
+func encodeTrack(degrees float64) byte {
+	for degrees < 0 {
+		degrees += 360
+	}
+	return byte(degrees / (360.0 / 256.0))
+}
+
+func encodeCallsign(callsign string) [8]byte {
+	var out [8]byte
+	padded := strings.ToUpper(callsign)
+	if len(padded) > 8 {
+		padded = padded[:8]
+	}
+	for len(padded) < 8 {
+		padded += " "
+	}
+	copy(out[:], padded)
+	return out
+}
+
+// TrafficReport builds an unframed GDL90 Traffic Report (message ID
+// 0x14) for a decoded APRS position report. addr is normally the result
+// of ICAOAddressFromCallsign, since a relayed balloon has no real ICAO
+// address.
+func TrafficReport(addr uint32, p geospatial.Point, callsign string) []byte {
+	return trafficMessage(MsgIDTrafficReport, addr, p, callsign)
+}
+
+// Ownship builds an unframed GDL90 Ownship Report (message ID 0x0A),
+// describing the ground station itself rather than tracked traffic.
+func Ownship(addr uint32, p geospatial.Point, callsign string) []byte {
+	return trafficMessage(MsgIDOwnship, addr, p, callsign)
+}
+
+func trafficMessage(msgID byte, addr uint32, p geospatial.Point, callsign string) []byte {
+	msg := make([]byte, 28)
+	msg[0] = msgID
+
+	// Address type 0 = ADS-B with ICAO address would be the normal case;
+	// we flag 2 ("non-ICAO address") since this address was synthesized.
+	const addressTypeNonICAO = 2
+	msg[1] = addressTypeNonICAO
+
+	msg[2] = byte(addr >> 16)
+	msg[3] = byte(addr >> 8)
+	msg[4] = byte(addr)
+
+	lat := encodeLatLon(p.Lat)
+	msg[5] = byte(lat >> 16)
+	msg[6] = byte(lat >> 8)
+	msg[7] = byte(lat)
+
+	lon := encodeLatLon(p.Lon)
+	msg[8] = byte(lon >> 16)
+	msg[9] = byte(lon >> 8)
+	msg[10] = byte(lon)
+
+	alt := encodeAltitude(p.Altitude)
+	// "Airborne" + "true track" misc indicator bits, GDL90 section 3.5.1.
+	const miscAirborneTrueTrack = 0x9
+	msg[11] = byte(alt >> 4)
+	msg[12] = byte(alt<<4) | miscAirborneTrueTrack
+
+	msg[13] = byte(defaultNIC<<4) | defaultNACp
+
+	// Horizontal velocity is reported as "unavailable" (0xFFF) and
+	// vertical velocity as "no data" (0x800), since APRS position
+	// reports don't reliably carry either. These are distinct sentinels
+	// per the GDL90 spec, not the same bit pattern.
+	msg[14] = 0xFF
+	msg[15] = 0xF8
+	msg[16] = 0x00
+
+	msg[17] = encodeTrack(p.Heading)
+	msg[18] = EmitterCategoryBalloon
+
+	cs := encodeCallsign(callsign)
+	copy(msg[19:27], cs[:])
+
+	// Emergency/priority code 0 (none) in the high nibble, spare low nibble.
+	msg[27] = 0x00
+
+	return msg
+}
+
+// Heartbeat builds the unframed GDL90 Heartbeat message (0x00) that GDL90
+// receivers expect once per second to know the source is alive. ts is
+// used for the seconds-since-midnight-UTC timestamp field.
+func Heartbeat(ts time.Time, gpsValid bool, messageCount uint16) []byte {
+	msg := make([]byte, 7)
+	msg[0] = MsgIDHeartbeat
+
+	var status1 byte
+	if gpsValid {
+		status1 |= 0x80 // GPS position valid
+	}
+	status1 |= 0x01 // UAT initialized
+	msg[1] = status1
+
+	ts = ts.UTC()
+	midnight := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+	secs := uint32(ts.Sub(midnight).Seconds())
+
+	// The timestamp is 17 bits: bits 0-15 go in msg[3]/msg[4], and bit 16
+	// (needed once UTC time-of-day exceeds 65535s, i.e. after 18:12:15)
+	// lives in bit 7 of status byte 2.
+	var status2 byte
+	if secs&0x10000 != 0 {
+		status2 |= 0x80
+	}
+	msg[2] = status2
+
+	msg[3] = byte(secs)
+	msg[4] = byte(secs >> 8)
+
+	msg[5] = byte(messageCount >> 8)
+	msg[6] = byte(messageCount)
+
+	return msg
+}
@@ -0,0 +1,99 @@
+// GoBalloon
+// geospatial.go - Common geospatial types shared across packages
+//
+// (c) 2018, Christopher Snell
+
+// Package geospatial provides the position/fix types that are passed
+// between GoBalloon's decoders (APRS, GNSS) and encoders (APRS, GDL90).
+package geospatial
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chrissnell/GoBalloon/pkg/gnss"
+)
+
+// AltitudeSource records which sensor (or fusion of sensors) produced a
+// Point's Altitude, so that downstream consumers can judge how much to
+// trust it.
+type AltitudeSource int
+
+// Altitude sources set by the fusion policies in the aprs package.
+const (
+	AltitudeSourceUnknown AltitudeSource = iota
+	AltitudeSourceGPS
+	AltitudeSourceBaro
+	AltitudeSourceFused
+)
+
+// Point represents a position fix, along with whatever ancillary data
+// (altitude, heading, speed, fix quality, ...) the source was able to
+// provide about it.
+type Point struct {
+	Lat float64
+	Lon float64
+
+	// Altitude is in feet, per the APRS convention. It may be GPS-derived,
+	// barometer-derived, or a fusion of the two; see AltitudeSource.
+	Altitude       float64
+	AltitudeSource AltitudeSource
+
+	// PressureAltitude is the barometer-derived altitude, in feet, kept
+	// alongside GPS altitude so that both can be logged independently
+	// and the flight profile reconstructed after recovery.
+	PressureAltitude float64
+
+	// Temperature is the barometric sensor's die temperature, in Celsius.
+	Temperature float64
+
+	Heading float64
+	Speed   float64
+
+	RadioRange float64
+
+	// The fields below carry a GNSS fix's quality data (see gnss.Fix) so
+	// that downstream consumers can judge fix trust programmatically,
+	// not just from a formatted comment string.
+	FixQuality      gnss.FixQuality
+	HDOP            float64
+	SatsInUse       int
+	SatsInView      int
+	HAE             float64 // height above the WGS84 ellipsoid, in meters
+	GeoidSeparation float64 // WGS84 ellipsoid minus MSL, in meters; MSL = HAE - GeoidSeparation
+
+	Time           time.Time
+	MessageCapable bool
+}
+
+// ApplyGNSSFix copies a gnss.Fix's quality data onto the point, leaving
+// Lat/Lon/Time untouched so that callers can decide separately whether
+// to trust the GNSS fix's position over some other source.
+func (p *Point) ApplyGNSSFix(fix *gnss.Fix) {
+	p.FixQuality = fix.Quality
+	p.HDOP = fix.HDOP
+	p.SatsInUse = fix.SatsInUse
+	p.SatsInView = fix.SatsInView
+	p.HAE = fix.HAE
+	p.GeoidSeparation = fix.GeoidSeparation
+}
+
+// LatDecimalDegreesToDegreesDecimalMinutes converts a latitude in decimal
+// degrees to the "ddmm.mm" degrees-decimal-minutes format used by
+// uncompressed APRS position reports.
+func LatDecimalDegreesToDegreesDecimalMinutes(lat float64) string {
+	degrees := int(lat)
+	minutes := (lat - float64(degrees)) * 60
+
+	return fmt.Sprintf("%02d%05.2f", degrees, minutes)
+}
+
+// LonDecimalDegreesToDegreesDecimalMinutes converts a longitude in decimal
+// degrees to the "dddmm.mm" degrees-decimal-minutes format used by
+// uncompressed APRS position reports.
+func LonDecimalDegreesToDegreesDecimalMinutes(lon float64) string {
+	degrees := int(lon)
+	minutes := (lon - float64(degrees)) * 60
+
+	return fmt.Sprintf("%03d%05.2f", degrees, minutes)
+}
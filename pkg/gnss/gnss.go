@@ -0,0 +1,480 @@
+// GoBalloon
+// gnss.go - NMEA 0183 parser for multi-constellation GNSS receivers
+//
+// (c) 2018, Christopher Snell
+
+// Package gnss parses the NMEA sentences emitted by modern multi-GNSS
+// receivers ($GPGGA, $GNGGA, $GxGSV, $GxGSA, $GNRMC) and tracks per-
+// satellite fix quality so that it can be reported alongside a position,
+// which matters a great deal at burst altitude where a marginal fix is
+// common.
+package gnss
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Constellation identifies the GNSS system that a tracked satellite
+// belongs to, as distinguished by its NMEA PRN range.
+type Constellation int
+
+// Constellations recognized by the NMEA PRN ranges used by GGA/GSV/GSA
+// sentences.
+const (
+	ConstellationUnknown Constellation = iota
+	ConstellationGPS
+	ConstellationSBAS
+	ConstellationGLONASS
+	ConstellationGalileo
+	ConstellationBeiDou
+	ConstellationQZSS
+)
+
+// String returns the short name used in position-report comments, e.g. "GPS".
+func (c Constellation) String() string {
+	switch c {
+	case ConstellationGPS:
+		return "GPS"
+	case ConstellationSBAS:
+		return "SBAS"
+	case ConstellationGLONASS:
+		return "GLONASS"
+	case ConstellationGalileo:
+		return "GALILEO"
+	case ConstellationBeiDou:
+		return "BEIDOU"
+	case ConstellationQZSS:
+		return "QZSS"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ClassifyPRN maps a GSV/GSA satellite PRN to the constellation that owns
+// its PRN range, per the NMEA 0183 convention used by combined GNSS
+// receivers.
+func ClassifyPRN(prn int) Constellation {
+	switch {
+	case prn >= 1 && prn <= 32:
+		return ConstellationGPS
+	case prn >= 33 && prn <= 64:
+		return ConstellationSBAS
+	case prn >= 65 && prn <= 96:
+		return ConstellationGLONASS
+	case prn >= 193 && prn <= 200:
+		return ConstellationQZSS
+	case prn >= 201 && prn <= 235:
+		return ConstellationBeiDou
+	case prn >= 301 && prn <= 336:
+		return ConstellationGalileo
+	default:
+		return ConstellationUnknown
+	}
+}
+
+// SatelliteID is the identifier that a tracked satellite is keyed by: its
+// constellation and its PRN within that constellation. SBAS satellites
+// are conventionally displayed as "S"+PRN rather than their raw PRN.
+type SatelliteID struct {
+	Constellation Constellation
+	PRN           int
+}
+
+// letter returns the conventional single-letter RINEX-style satellite
+// system code (G/S/R/E/C/J), which - unlike the first letter of the
+// constellation's full name - uniquely distinguishes GPS from GLONASS.
+func (c Constellation) letter() byte {
+	switch c {
+	case ConstellationGPS:
+		return 'G'
+	case ConstellationSBAS:
+		return 'S'
+	case ConstellationGLONASS:
+		return 'R'
+	case ConstellationGalileo:
+		return 'E'
+	case ConstellationBeiDou:
+		return 'C'
+	case ConstellationQZSS:
+		return 'J'
+	default:
+		return '?'
+	}
+}
+
+// String renders the satellite ID the way operators expect to see it,
+// e.g. "G14" or "S138".
+func (s SatelliteID) String() string {
+	return fmt.Sprintf("%c%d", s.Constellation.letter(), s.PRN)
+}
+
+// SatelliteInfo tracks everything we know about one satellite across the
+// GSV/GSA sentences in a single NMEA cycle.
+type SatelliteInfo struct {
+	ID SatelliteID
+
+	Elevation int // degrees above the horizon, 0-90
+	Azimuth   int // degrees from true north, 0-359
+	SNR       int // dB-Hz, 0 if not tracked
+
+	InSolution bool // true if this satellite is used in the current fix (from GSA)
+	LastSeen   time.Time
+}
+
+// FixQuality mirrors the GGA "fix quality" indicator field.
+type FixQuality int
+
+// Fix qualities defined by NMEA 0183 for the GGA sentence.
+const (
+	FixInvalid FixQuality = iota
+	FixGPS
+	FixDGPS
+	FixPPS
+	FixRTK
+	FixFloatRTK
+	FixEstimated
+	FixManual
+	FixSimulation
+)
+
+// Fix is the aggregate result of parsing one NMEA cycle (GGA + GSV + GSA
+// + RMC sentences sharing a fix epoch).
+type Fix struct {
+	Lat, Lon float64
+
+	// HAE is height above the WGS84 ellipsoid, in meters, computed from
+	// GGA's antenna altitude (MSL) field plus GeoidSeparation.
+	HAE float64
+
+	// GeoidSeparation is the difference between the WGS84 ellipsoid and
+	// mean sea level (MSL) at this position, in meters. MSL altitude is
+	// HAE - GeoidSeparation.
+	GeoidSeparation float64
+
+	Quality    FixQuality
+	HDOP       float64
+	SatsInUse  int
+	SatsInView int
+	Time       time.Time
+}
+
+// Tracker accumulates SatelliteInfo across successive GSV/GSA sentences
+// and produces a Fix once a GGA or RMC sentence closes out the cycle.
+type Tracker struct {
+	satellites map[SatelliteID]*SatelliteInfo
+
+	// satsInView is the GSV sentence's own authoritative count of
+	// satellites visible this epoch, which may exceed len(satellites)
+	// if the receiver didn't report every visible satellite's detail.
+	satsInView int
+}
+
+// NewTracker returns an empty satellite Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{satellites: make(map[SatelliteID]*SatelliteInfo)}
+}
+
+// Satellites returns the satellites tracked in the current epoch, keyed by
+// SatelliteID. The set is discarded whenever a new GSV group begins, and
+// the returned map is the tracker's own, not a copy.
+func (t *Tracker) Satellites() map[SatelliteID]*SatelliteInfo {
+	return t.satellites
+}
+
+// Parse feeds one NMEA sentence (with or without its trailing checksum)
+// into the tracker and, if the sentence was a GGA or RMC, returns the Fix
+// it produced.
+func (t *Tracker) Parse(sentence string) (*Fix, error) {
+	sentence = strings.TrimSpace(sentence)
+	if idx := strings.IndexByte(sentence, '*'); idx != -1 {
+		sentence = sentence[:idx]
+	}
+	if !strings.HasPrefix(sentence, "$") {
+		return nil, fmt.Errorf("gnss: not an NMEA sentence: %q", sentence)
+	}
+
+	fields := strings.Split(sentence[1:], ",")
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("gnss: empty sentence")
+	}
+
+	id := fields[0]
+	switch {
+	case strings.HasSuffix(id, "GGA"):
+		return t.parseGGA(fields)
+	case strings.HasSuffix(id, "GSV"):
+		return nil, t.parseGSV(fields)
+	case strings.HasSuffix(id, "GSA"):
+		return nil, t.parseGSA(fields)
+	case strings.HasSuffix(id, "RMC"):
+		return t.parseRMC(fields)
+	default:
+		return nil, nil
+	}
+}
+
+func (t *Tracker) parseGGA(f []string) (*Fix, error) {
+	if len(f) < 15 {
+		return nil, fmt.Errorf("gnss: short GGA sentence: %d fields", len(f))
+	}
+
+	fix := &Fix{}
+
+	var err error
+	fix.Time, err = parseNMEATime(f[1])
+	if err != nil {
+		return nil, fmt.Errorf("gnss: GGA time: %v", err)
+	}
+
+	fix.Lat, err = parseNMEALat(f[2], f[3])
+	if err != nil {
+		return nil, fmt.Errorf("gnss: GGA latitude: %v", err)
+	}
+	fix.Lon, err = parseNMEALon(f[4], f[5])
+	if err != nil {
+		return nil, fmt.Errorf("gnss: GGA longitude: %v", err)
+	}
+
+	q, err := strconv.Atoi(f[6])
+	if err != nil {
+		return nil, fmt.Errorf("gnss: GGA fix quality: %v", err)
+	}
+	fix.Quality = FixQuality(q)
+
+	fix.SatsInUse, err = strconv.Atoi(f[7])
+	if err != nil {
+		return nil, fmt.Errorf("gnss: GGA sats in use: %v", err)
+	}
+
+	fix.HDOP, err = strconv.ParseFloat(f[8], 64)
+	if err != nil {
+		return nil, fmt.Errorf("gnss: GGA HDOP: %v", err)
+	}
+
+	mslAltitude, err := strconv.ParseFloat(f[9], 64)
+	if err != nil {
+		return nil, fmt.Errorf("gnss: GGA altitude: %v", err)
+	}
+
+	fix.GeoidSeparation, err = strconv.ParseFloat(f[11], 64)
+	if err != nil {
+		return nil, fmt.Errorf("gnss: GGA geoid separation: %v", err)
+	}
+
+	fix.HAE = mslAltitude + fix.GeoidSeparation
+
+	fix.SatsInView = t.satsInView
+
+	return fix, nil
+}
+
+func (t *Tracker) parseRMC(f []string) (*Fix, error) {
+	if len(f) < 10 {
+		return nil, fmt.Errorf("gnss: short RMC sentence: %d fields", len(f))
+	}
+	if f[2] != "A" {
+		// Void fix; nothing more to report.
+		return nil, nil
+	}
+
+	fix := &Fix{}
+	var err error
+
+	fix.Lat, err = parseNMEALat(f[3], f[4])
+	if err != nil {
+		return nil, fmt.Errorf("gnss: RMC latitude: %v", err)
+	}
+	fix.Lon, err = parseNMEALon(f[5], f[6])
+	if err != nil {
+		return nil, fmt.Errorf("gnss: RMC longitude: %v", err)
+	}
+
+	fix.SatsInView = t.satsInView
+
+	return fix, nil
+}
+
+// parseGSV updates elevation/azimuth/SNR for each satellite reported in a
+// GSV sentence. GSV sentences are split across multiple messages, one
+// per up-to-4 satellites, covering the satellites visible in the current
+// epoch. Message 1 of a new GSV group starts a fresh epoch, so the
+// tracker is reset at that point rather than accumulating satellites
+// that may have long since set below the horizon.
+func (t *Tracker) parseGSV(f []string) error {
+	// $GxGSV,totalMsgs,msgNum,satsInView,[prn,elev,azim,snr]*4,checksum
+	if len(f) < 4 {
+		return fmt.Errorf("gnss: short GSV sentence: %d fields", len(f))
+	}
+
+	if f[2] == "1" {
+		t.satellites = make(map[SatelliteID]*SatelliteInfo)
+	}
+
+	satsInView, err := strconv.Atoi(f[3])
+	if err != nil {
+		return fmt.Errorf("gnss: GSV sats in view: %v", err)
+	}
+	t.satsInView = satsInView
+
+	now := time.Now()
+
+	for i := 4; i+3 < len(f)+1 && i+3 <= len(f); i += 4 {
+		prnStr := f[i]
+		if prnStr == "" {
+			continue
+		}
+		prn, err := strconv.Atoi(prnStr)
+		if err != nil {
+			return fmt.Errorf("gnss: GSV PRN: %v", err)
+		}
+
+		id := SatelliteID{Constellation: ClassifyPRN(prn), PRN: prn}
+		info := t.satellites[id]
+		if info == nil {
+			info = &SatelliteInfo{ID: id}
+			t.satellites[id] = info
+		}
+
+		info.Elevation, _ = strconv.Atoi(f[i+1])
+		info.Azimuth, _ = strconv.Atoi(f[i+2])
+		if f[i+3] != "" {
+			info.SNR, _ = strconv.Atoi(f[i+3])
+		}
+		info.LastSeen = now
+	}
+
+	return nil
+}
+
+// parseGSA marks which satellites are part of the current solution,
+// adding any PRN that no GSV sentence has reported yet. It never clears
+// an InSolution flag itself: flags go away only when parseGSV starts a
+// new epoch (GSV message 1) and discards the whole satellite set, so
+// without GSV sentences a satellite that leaves the solution keeps
+// reporting InSolution=true.
+func (t *Tracker) parseGSA(f []string) error {
+	// $GxGSA,mode1,mode2,prn1..prn12,pdop,hdop,vdop*checksum
+	if len(f) < 18 {
+		return fmt.Errorf("gnss: short GSA sentence: %d fields", len(f))
+	}
+
+	for _, p := range f[3:15] {
+		if p == "" {
+			continue
+		}
+		prn, err := strconv.Atoi(p)
+		if err != nil {
+			continue
+		}
+		id := SatelliteID{Constellation: ClassifyPRN(prn), PRN: prn}
+		info := t.satellites[id]
+		if info == nil {
+			info = &SatelliteInfo{ID: id}
+			t.satellites[id] = info
+		}
+		info.InSolution = true
+	}
+
+	return nil
+}
+
+func parseNMEATime(v string) (time.Time, error) {
+	if len(v) < 6 {
+		return time.Time{}, fmt.Errorf("malformed time %q", v)
+	}
+	hh, err := strconv.Atoi(v[0:2])
+	if err != nil {
+		return time.Time{}, err
+	}
+	mm, err := strconv.Atoi(v[2:4])
+	if err != nil {
+		return time.Time{}, err
+	}
+	ss, err := strconv.Atoi(v[4:6])
+	if err != nil {
+		return time.Time{}, err
+	}
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), hh, mm, ss, 0, time.UTC), nil
+}
+
+func parseNMEALat(v, hemi string) (float64, error) {
+	if len(v) < 4 {
+		return 0, fmt.Errorf("malformed latitude %q", v)
+	}
+	deg, err := strconv.ParseFloat(v[0:2], 64)
+	if err != nil {
+		return 0, err
+	}
+	min, err := strconv.ParseFloat(v[2:], 64)
+	if err != nil {
+		return 0, err
+	}
+	lat := deg + min/60
+	if hemi == "S" {
+		lat = -lat
+	}
+	return lat, nil
+}
+
+// Comment builds the standardized "[GPS 9/12 HDOP 0.9 SBAS]"-style fix
+// summary that gets appended to APRS position report comments so that
+// igate operators can gauge fix trust at a glance.
+func (t *Tracker) Comment(fix *Fix) string {
+	counts := make(map[Constellation]int)
+	for _, info := range t.satellites {
+		if info.InSolution {
+			counts[info.ID.Constellation]++
+		}
+	}
+
+	// Candidates are checked in a fixed order so that a tie goes to the
+	// one listed first (GPS wins any tie).
+	primary := ConstellationGPS
+	best := 0
+	for _, c := range []Constellation{ConstellationGPS, ConstellationGLONASS, ConstellationGalileo, ConstellationBeiDou, ConstellationQZSS, ConstellationUnknown} {
+		if n := counts[c]; n > best {
+			primary, best = c, n
+		}
+	}
+
+	var extras []string
+	for _, c := range []Constellation{ConstellationSBAS, ConstellationGLONASS, ConstellationGalileo, ConstellationBeiDou, ConstellationQZSS} {
+		if c == primary {
+			continue
+		}
+		if counts[c] > 0 {
+			extras = append(extras, c.String())
+		}
+	}
+
+	comment := fmt.Sprintf("[%s %d/%d HDOP %.1f", primary, fix.SatsInUse, fix.SatsInView, fix.HDOP)
+	for _, e := range extras {
+		comment += " " + e
+	}
+	comment += "]"
+
+	return comment
+}
+
+func parseNMEALon(v, hemi string) (float64, error) {
+	if len(v) < 5 {
+		return 0, fmt.Errorf("malformed longitude %q", v)
+	}
+	deg, err := strconv.ParseFloat(v[0:3], 64)
+	if err != nil {
+		return 0, err
+	}
+	min, err := strconv.ParseFloat(v[3:], 64)
+	if err != nil {
+		return 0, err
+	}
+	lon := deg + min/60
+	if hemi == "W" {
+		lon = -lon
+	}
+	return lon, nil
+}
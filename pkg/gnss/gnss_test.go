@@ -0,0 +1,202 @@
+// GoBalloon
+// gnss_test.go - tests for the NMEA 0183 multi-constellation parser
+//
+// (c) 2026, GoBalloon contributors
+
+package gnss
+
+import "testing"
+
+func TestClassifyPRN(t *testing.T) {
+	cases := []struct {
+		prn  int
+		want Constellation
+	}{
+		{1, ConstellationGPS},
+		{32, ConstellationGPS},
+		{33, ConstellationSBAS},
+		{64, ConstellationSBAS},
+		{65, ConstellationGLONASS},
+		{96, ConstellationGLONASS},
+		{195, ConstellationQZSS},
+		{210, ConstellationBeiDou},
+		{301, ConstellationGalileo},
+		{1000, ConstellationUnknown},
+	}
+
+	for _, c := range cases {
+		if got := ClassifyPRN(c.prn); got != c.want {
+			t.Errorf("ClassifyPRN(%d) = %v, want %v", c.prn, got, c.want)
+		}
+	}
+}
+
+func TestSatelliteIDString(t *testing.T) {
+	cases := []struct {
+		id   SatelliteID
+		want string
+	}{
+		{SatelliteID{ConstellationGPS, 14}, "G14"},
+		{SatelliteID{ConstellationGLONASS, 65}, "R65"},
+		{SatelliteID{ConstellationSBAS, 138}, "S138"},
+		{SatelliteID{ConstellationBeiDou, 206}, "C206"},
+	}
+
+	for _, c := range cases {
+		if got := c.id.String(); got != c.want {
+			t.Errorf("%+v.String() = %q, want %q", c.id, got, c.want)
+		}
+	}
+
+	if g, r := (SatelliteID{ConstellationGPS, 14}).String(), (SatelliteID{ConstellationGLONASS, 14}).String(); g == r {
+		t.Errorf("GPS and GLONASS satellite IDs must be distinguishable, both rendered %q", g)
+	}
+}
+
+func TestParseGGAComputesHAEFromMSLAndGeoidSeparation(t *testing.T) {
+	tr := NewTracker()
+
+	// Real-world example: MSL altitude 545.4m, geoid separation 46.9m,
+	// so HAE (the ellipsoid height) should be their sum, not a copy of
+	// the MSL figure.
+	fix, err := tr.Parse("$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if fix == nil {
+		t.Fatal("Parse returned a nil Fix for a GGA sentence")
+	}
+
+	const wantHAE = 545.4 + 46.9
+	if fix.HAE != wantHAE {
+		t.Errorf("fix.HAE = %v, want %v", fix.HAE, wantHAE)
+	}
+	if fix.GeoidSeparation != 46.9 {
+		t.Errorf("fix.GeoidSeparation = %v, want 46.9", fix.GeoidSeparation)
+	}
+
+	wantMSL := fix.HAE - fix.GeoidSeparation
+	if wantMSL != 545.4 {
+		t.Errorf("HAE - GeoidSeparation = %v, want the original MSL altitude 545.4", wantMSL)
+	}
+}
+
+func TestParseGSVResetsOnNewEpoch(t *testing.T) {
+	tr := NewTracker()
+
+	if _, err := tr.Parse("$GPGSV,1,1,01,01,45,180,40"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := len(tr.Satellites()); got != 1 {
+		t.Fatalf("after first GSV cycle, len(Satellites()) = %d, want 1", got)
+	}
+	if tr.satsInView != 1 {
+		t.Fatalf("satsInView = %d, want 1", tr.satsInView)
+	}
+
+	// A satellite that has since set below the horizon is dropped from
+	// a later epoch's GSV group; the tracker must not keep reporting it.
+	if _, err := tr.Parse("$GPGSV,1,1,01,02,10,090,30"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	sats := tr.Satellites()
+	if len(sats) != 1 {
+		t.Fatalf("after second GSV cycle, len(Satellites()) = %d, want 1", len(sats))
+	}
+	if _, stillTracked := sats[SatelliteID{ConstellationGPS, 1}]; stillTracked {
+		t.Error("satellite PRN 1 from the prior epoch is still tracked after a new GSV cycle began")
+	}
+}
+
+func TestParseGGAUsesGSVReportedSatsInView(t *testing.T) {
+	tr := NewTracker()
+
+	// The receiver only details 1 satellite but claims 12 are visible;
+	// SatsInView must reflect the sentence's own count, not len(satellites).
+	if _, err := tr.Parse("$GPGSV,3,1,12,01,45,180,40"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	fix, err := tr.Parse("$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if fix.SatsInView != 12 {
+		t.Errorf("fix.SatsInView = %d, want 12", fix.SatsInView)
+	}
+}
+
+func TestParseGSADoesNotCreatePhantomSatelliteFromFixTypeField(t *testing.T) {
+	tr := NewTracker()
+
+	// The canonical GSA example: field 2 ("3") is the fix type, not a
+	// PRN. The 12 PRN slots are 04,05,,09,12,,,24,,,,, - PRN 3 must never
+	// appear as a tracked satellite.
+	if _, err := tr.Parse("$GPGSA,A,3,04,05,,09,12,,,24,,,,,2.5,1.3,2.1*39"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	sats := tr.Satellites()
+	if _, phantom := sats[SatelliteID{ConstellationGPS, 3}]; phantom {
+		t.Error("GSA's fix-type field was parsed as PRN 3, creating a phantom satellite")
+	}
+
+	for _, prn := range []int{4, 5, 9, 12, 24} {
+		info, ok := sats[SatelliteID{ConstellationGPS, prn}]
+		if !ok || !info.InSolution {
+			t.Errorf("PRN %d should be tracked and InSolution after the GSA sentence", prn)
+		}
+	}
+	if got := len(sats); got != 5 {
+		t.Errorf("len(Satellites()) = %d, want 5 (only the 5 populated PRN slots)", got)
+	}
+}
+
+func TestGSAInSolutionDoesNotPersistAcrossEpochs(t *testing.T) {
+	tr := NewTracker()
+
+	if _, err := tr.Parse("$GPGSV,1,1,01,01,45,180,40"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := tr.Parse("$GPGSA,A,3,01,,,,,,,,,,,,1.2,0.9,0.8*2C"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !tr.Satellites()[SatelliteID{ConstellationGPS, 1}].InSolution {
+		t.Fatal("satellite PRN 1 should be marked InSolution after its GSA sentence")
+	}
+
+	// PRN 1 drops out of view in the next epoch and a different
+	// satellite takes its place; a stale InSolution flag must not
+	// survive since the satellite itself is no longer tracked at all.
+	if _, err := tr.Parse("$GPGSV,1,1,01,02,10,090,30"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	counts := 0
+	for _, info := range tr.Satellites() {
+		if info.InSolution {
+			counts++
+		}
+	}
+	if counts != 0 {
+		t.Errorf("InSolution count after the new epoch = %d, want 0 (no GSA yet reported for it)", counts)
+	}
+}
+
+func TestCommentDistinguishesPrimaryConstellation(t *testing.T) {
+	tr := NewTracker()
+
+	if _, err := tr.Parse("$GPGSV,1,1,02,01,45,180,40,65,30,090,35"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := tr.Parse("$GPGSA,A,3,01,65,,,,,,,,,,,1.2,0.9,0.8*2D"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	fix := &Fix{SatsInUse: 2, SatsInView: 2, HDOP: 0.9}
+	comment := tr.Comment(fix)
+
+	const want = "[GPS 2/2 HDOP 0.9 GLONASS]"
+	if comment != want {
+		t.Errorf("Comment() = %q, want %q", comment, want)
+	}
+}
@@ -0,0 +1,69 @@
+// GoBalloon
+// reader.go - Reads back a replay log for offline debugging
+//
+// (c) 2018, Christopher Snell
+
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Reader reads back the Entry records written by a Logger, in order.
+type Reader struct {
+	file *os.File
+	gz   *gzip.Reader
+	scan *bufio.Scanner
+}
+
+// OpenReader opens a gzipped replay log written by Logger for sequential
+// reading.
+func OpenReader(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: opening %q: %v", path, err)
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("replay: %q is not a gzipped replay log: %v", path, err)
+	}
+
+	return &Reader{file: f, gz: gz, scan: bufio.NewScanner(gz)}, nil
+}
+
+// Next returns the next Entry in the log, its raw bytes decoded from
+// hex, or io.EOF once the log is exhausted.
+func (r *Reader) Next() (Entry, []byte, error) {
+	if !r.scan.Scan() {
+		if err := r.scan.Err(); err != nil {
+			return Entry{}, nil, fmt.Errorf("replay: reading log: %v", err)
+		}
+		return Entry{}, nil, io.EOF
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(r.scan.Bytes(), &entry); err != nil {
+		return Entry{}, nil, fmt.Errorf("replay: decoding entry: %v", err)
+	}
+
+	data, err := hex.DecodeString(entry.HexBytes)
+	if err != nil {
+		return entry, nil, fmt.Errorf("replay: decoding hex payload: %v", err)
+	}
+
+	return entry, data, nil
+}
+
+// Close releases the underlying file and gzip reader.
+func (r *Reader) Close() error {
+	r.gz.Close()
+	return r.file.Close()
+}
@@ -0,0 +1,157 @@
+// GoBalloon
+// replay.go - Rotating, gzipped raw-frame logging for offline replay
+//
+// (c) 2018, Christopher Snell
+
+// Package replay records every raw AX.25 frame GoBalloon sends or
+// receives to a rotating, gzip-compressed, one-JSON-object-per-line log,
+// so that parser regressions can be debugged offline by re-feeding a
+// captured flight through the decoder with the gobinreplay tool.
+package replay
+
+import (
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Direction distinguishes a transmitted frame from a received one.
+type Direction string
+
+// Frame directions recorded in the log.
+const (
+	DirectionTX Direction = "tx"
+	DirectionRX Direction = "rx"
+)
+
+// Entry is one logged frame. Timestamp is a monotonic count of
+// nanoseconds since the Logger was created, not a wall-clock time, so
+// that replays can reproduce the original inter-frame timing regardless
+// of when the replay happens.
+type Entry struct {
+	TimestampNS int64     `json:"t"`
+	Direction   Direction `json:"dir"`
+	HexBytes    string    `json:"hex"`
+	Summary     string    `json:"summary,omitempty"`
+}
+
+// Logger writes Entry records to a gzip-compressed, rotating log file.
+// It is safe for concurrent use.
+type Logger struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	started  time.Time
+	file     *os.File
+	gz       *gzip.Writer
+	counter  *countingWriter
+	written  int64
+}
+
+// countingWriter tallies the bytes actually written to an underlying
+// io.Writer, as opposed to the uncompressed bytes a gzip.Writer accepts
+// before it has flushed them.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// NewLogger opens the first log file under dir (created if necessary)
+// and returns a Logger that rotates to a new file once the compressed
+// output would exceed maxBytes.
+func NewLogger(dir string, maxBytes int64) (*Logger, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("replay: creating log dir: %v", err)
+	}
+
+	l := &Logger{dir: dir, maxBytes: maxBytes, started: time.Now()}
+	if err := l.rotate(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Write appends one Entry for frame data, travelling in direction dir,
+// with an optional human-readable summary (e.g. "position report from N0CALL-7").
+func (l *Logger) Write(dir Direction, data []byte, summary string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := Entry{
+		TimestampNS: time.Since(l.started).Nanoseconds(),
+		Direction:   dir,
+		HexBytes:    hex.EncodeToString(data),
+		Summary:     summary,
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("replay: encoding entry: %v", err)
+	}
+	line = append(line, '\n')
+
+	if l.maxBytes > 0 && l.written+int64(len(line)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := l.gz.Write(line); err != nil {
+		return fmt.Errorf("replay: writing entry: %v", err)
+	}
+	if err := l.gz.Flush(); err != nil {
+		return fmt.Errorf("replay: flushing entry: %v", err)
+	}
+
+	l.written = l.counter.n
+	return nil
+}
+
+// rotate closes the current log file, if any, and opens a new one named
+// for the current wall-clock time.
+func (l *Logger) rotate() error {
+	if l.gz != nil {
+		l.gz.Close()
+	}
+	if l.file != nil {
+		l.file.Close()
+	}
+
+	name := fmt.Sprintf("%s/goballoon-%s.jsonl.gz", l.dir, time.Now().UTC().Format("20060102-150405"))
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("replay: creating log file: %v", err)
+	}
+
+	l.file = f
+	l.counter = &countingWriter{w: f}
+	l.gz = gzip.NewWriter(l.counter)
+	l.written = 0
+
+	return nil
+}
+
+// Close flushes and closes the current log file.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.gz != nil {
+		l.gz.Close()
+	}
+	if l.file != nil {
+		return l.file.Close()
+	}
+	return nil
+}
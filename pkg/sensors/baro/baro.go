@@ -0,0 +1,107 @@
+// GoBalloon
+// baro.go - Barometric altitude sensing for BMP180/BMP280/MS5611
+//
+// (c) 2018, Christopher Snell
+
+// Package baro reads pressure (and, where available, temperature) from
+// I2C barometric sensors and converts it to altitude using the ISA
+// barometric formula. GPS altitude is known to glitch near 60 kft and
+// above on high-altitude balloon flights, so a barometric cross-check
+// is standard practice on HAB payloads.
+package baro
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bus is the minimal I2C access that a Sensor needs. It is satisfied by
+// most I2C bus implementations; GoBalloon does not depend on a specific
+// one so that the payload's main program can choose whatever driver
+// matches its hardware.
+type Bus interface {
+	ReadReg(addr uint8, reg uint8, p []byte) error
+	WriteReg(addr uint8, reg uint8, p []byte) error
+}
+
+// Sensor is implemented by each supported barometric sensor driver.
+type Sensor interface {
+	// ReadPressure returns station pressure in hPa.
+	ReadPressure() (float64, error)
+	// ReadTemperature returns the sensor's die temperature in Celsius.
+	ReadTemperature() (float64, error)
+}
+
+// Default sea-level pressure, in hPa, used when no QNH has been supplied
+// by a ground station or flight computer.
+const StandardQNH = 1013.25
+
+// isaZone describes one layer of the International Standard Atmosphere:
+// the base altitude and pressure at the bottom of the layer, and the
+// temperature lapse rate (K/m) within it. A lapse rate of 0 denotes an
+// isothermal layer.
+type isaZone struct {
+	baseAltitudeM float64
+	basePressure  float64 // hPa, relative to StandardQNH at the surface
+	baseTempK     float64
+	lapseRate     float64 // K/m, positive means temperature falls with altitude
+}
+
+// Standard ISA layers up to 32km. basePressure values are the well-known
+// ISA reference pressures for each layer boundary.
+var isaZones = []isaZone{
+	{0, 1013.25, 288.15, 0.0065},
+	{11000, 226.32, 216.65, 0},
+	{20000, 54.748, 216.65, -0.001},
+	{32000, 8.680, 228.65, -0.0028},
+}
+
+const (
+	gravity        = 9.80665
+	gasConstantAir = 287.053
+)
+
+// Altitude converts a station pressure reading (hPa) to altitude in
+// meters above mean sea level, given a QNH (hPa) to correct for local
+// barometric pressure. Above 32km, where the payload's sensor is no
+// longer within the standard ISA table, the last known lapse-rate zone
+// is extrapolated rather than failing outright, since HAB flights
+// routinely burst well above that altitude.
+func Altitude(pressureHPa, qnhHPa float64) float64 {
+	if qnhHPa <= 0 {
+		qnhHPa = StandardQNH
+	}
+
+	// Correct the reading to the pressure it would be at the surface
+	// under standard conditions, accounting for local QNH.
+	p := pressureHPa * (StandardQNH / qnhHPa)
+
+	zone := isaZones[0]
+	for _, z := range isaZones {
+		if p <= z.basePressure {
+			zone = z
+			continue
+		}
+		break
+	}
+
+	if zone.lapseRate == 0 {
+		// Isothermal layer: h = h_b - (R*T_b/g) * ln(P/P_b)
+		return zone.baseAltitudeM - (gasConstantAir*zone.baseTempK/gravity)*math.Log(p/zone.basePressure)
+	}
+
+	// h = h_b + (T_b/L) * (1 - (P/P_b)^(R*L/g))
+	exponent := (gasConstantAir * zone.lapseRate) / gravity
+	return zone.baseAltitudeM + (zone.baseTempK/zone.lapseRate)*(1-math.Pow(p/zone.basePressure, exponent))
+}
+
+// AltitudeFeet is a convenience wrapper around Altitude that returns the
+// result in feet, matching the unit that the rest of GoBalloon's APRS
+// code uses for geospatial.Point.Altitude.
+func AltitudeFeet(pressureHPa, qnhHPa float64) float64 {
+	return Altitude(pressureHPa, qnhHPa) * 3.28084
+}
+
+// ErrNoSensor is returned by a driver constructor when its I2C address
+// does not respond with the expected chip ID.
+var ErrNoSensor = fmt.Errorf("baro: no sensor found at expected I2C address")
@@ -0,0 +1,84 @@
+// GoBalloon
+// baro_test.go - tests for ISA altitude conversion and fake I2C fixtures shared
+// by the BMP180/BMP280/MS5611 driver tests
+//
+// (c) 2026, GoBalloon contributors
+
+package baro
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// fakeBus is a stub Bus that serves canned register reads, for
+// exercising a driver without real I2C hardware. Most registers are
+// served straight out of regs; the BMP180 shares one data register
+// between its temperature and pressure conversions, distinguished only
+// by which command was last written to ctrlReg, so once a command
+// (masked by cmdMask) that has an entry in cmdData has been written,
+// every register read returns that entry instead of regs.
+type fakeBus struct {
+	regs    map[uint8][]byte
+	ctrlReg uint8
+	cmdMask uint8
+	cmdData map[uint8][]byte
+	lastCmd uint8
+}
+
+func (f *fakeBus) ReadReg(addr uint8, reg uint8, p []byte) error {
+	if data, ok := f.cmdData[f.lastCmd]; ok {
+		copy(p, data)
+		return nil
+	}
+	copy(p, f.regs[reg])
+	return nil
+}
+
+func (f *fakeBus) WriteReg(addr uint8, reg uint8, p []byte) error {
+	if reg == f.ctrlReg && len(p) > 0 {
+		f.lastCmd = p[0] &^ f.cmdMask
+	}
+	return nil
+}
+
+func u16le(v uint16) []byte { b := make([]byte, 2); binary.LittleEndian.PutUint16(b, v); return b }
+func i16le(v int16) []byte  { return u16le(uint16(v)) }
+func u16be(v uint16) []byte { b := make([]byte, 2); binary.BigEndian.PutUint16(b, v); return b }
+func i16be(v int16) []byte  { return u16be(uint16(v)) }
+
+func TestAltitudeIsZeroAtStandardQNH(t *testing.T) {
+	if got := Altitude(StandardQNH, StandardQNH); math.Abs(got) > 0.01 {
+		t.Errorf("Altitude(StandardQNH, StandardQNH) = %v, want ~0", got)
+	}
+}
+
+func TestAltitudeMatchesISALayerBoundary(t *testing.T) {
+	// 226.32 hPa is the ISA reference pressure at the troposphere/
+	// tropopause boundary, 11,000m.
+	got := Altitude(226.32, StandardQNH)
+	if math.Abs(got-11000) > 1 {
+		t.Errorf("Altitude(226.32, StandardQNH) = %v, want ~11000", got)
+	}
+}
+
+func TestAltitudeFeetConvertsFromMeters(t *testing.T) {
+	metric := Altitude(500, StandardQNH)
+	feet := AltitudeFeet(500, StandardQNH)
+	if math.Abs(feet-metric*3.28084) > 0.001 {
+		t.Errorf("AltitudeFeet(500, StandardQNH) = %v, want %v", feet, metric*3.28084)
+	}
+}
+
+func TestAltitudeAppliesQNHCorrection(t *testing.T) {
+	// Altitude scales the reading by StandardQNH/qnhHPa before looking it
+	// up in the ISA table, so for a fixed sensor reading, raising QNH
+	// shrinks the corrected pressure and therefore reports a higher
+	// computed altitude.
+	low := Altitude(900, 1000)
+	high := Altitude(900, 1030)
+	if !(high > low) {
+		t.Errorf("Altitude with higher QNH = %v, want greater than Altitude with lower QNH = %v", high, low)
+	}
+}
@@ -0,0 +1,132 @@
+// GoBalloon
+// bmp180.go - Bosch BMP180 I2C driver
+//
+// (c) 2018, Christopher Snell
+
+package baro
+
+import "encoding/binary"
+
+const (
+	bmp180Addr         = 0x77
+	bmp180RegCalib     = 0xAA
+	bmp180RegCtrl      = 0xF4
+	bmp180RegData      = 0xF6
+	bmp180CmdReadTemp  = 0x2E
+	bmp180CmdReadPress = 0x34
+	bmp180Oversampling = 3 // 0-3, higher is slower but less noisy
+)
+
+// BMP180 drives a Bosch BMP180 pressure/temperature sensor.
+type BMP180 struct {
+	bus Bus
+
+	ac1, ac2, ac3      int16
+	ac4, ac5, ac6      uint16
+	b1, b2, mb, mc, md int16
+}
+
+// NewBMP180 reads the sensor's factory calibration block and returns a
+// ready-to-use driver. The BMP180 lives at a fixed I2C address, unlike
+// its successors, so there is no address parameter.
+func NewBMP180(bus Bus) (*BMP180, error) {
+	raw := make([]byte, 22)
+	if err := bus.ReadReg(bmp180Addr, bmp180RegCalib, raw); err != nil {
+		return nil, err
+	}
+
+	s := &BMP180{bus: bus}
+	s.ac1 = int16(binary.BigEndian.Uint16(raw[0:2]))
+	s.ac2 = int16(binary.BigEndian.Uint16(raw[2:4]))
+	s.ac3 = int16(binary.BigEndian.Uint16(raw[4:6]))
+	s.ac4 = binary.BigEndian.Uint16(raw[6:8])
+	s.ac5 = binary.BigEndian.Uint16(raw[8:10])
+	s.ac6 = binary.BigEndian.Uint16(raw[10:12])
+	s.b1 = int16(binary.BigEndian.Uint16(raw[12:14]))
+	s.b2 = int16(binary.BigEndian.Uint16(raw[14:16]))
+	s.mb = int16(binary.BigEndian.Uint16(raw[16:18]))
+	s.mc = int16(binary.BigEndian.Uint16(raw[18:20]))
+	s.md = int16(binary.BigEndian.Uint16(raw[20:22]))
+
+	return s, nil
+}
+
+func (s *BMP180) readRawTemp() (int32, error) {
+	if err := s.bus.WriteReg(bmp180Addr, bmp180RegCtrl, []byte{bmp180CmdReadTemp}); err != nil {
+		return 0, err
+	}
+	data := make([]byte, 2)
+	if err := s.bus.ReadReg(bmp180Addr, bmp180RegData, data); err != nil {
+		return 0, err
+	}
+	return int32(binary.BigEndian.Uint16(data)), nil
+}
+
+func (s *BMP180) readRawPressure() (int32, error) {
+	cmd := bmp180CmdReadPress | (bmp180Oversampling << 6)
+	if err := s.bus.WriteReg(bmp180Addr, bmp180RegCtrl, []byte{byte(cmd)}); err != nil {
+		return 0, err
+	}
+	data := make([]byte, 3)
+	if err := s.bus.ReadReg(bmp180Addr, bmp180RegData, data); err != nil {
+		return 0, err
+	}
+	raw := (int32(data[0])<<16 | int32(data[1])<<8 | int32(data[2])) >> (8 - bmp180Oversampling)
+	return raw, nil
+}
+
+// b5 is the intermediate value that both the datasheet's temperature and
+// pressure compensation formulas are built on.
+func (s *BMP180) b5(rawTemp int32) int32 {
+	x1 := (rawTemp - int32(s.ac6)) * int32(s.ac5) / 32768
+	x2 := int32(s.mc) * 2048 / (x1 + int32(s.md))
+	return x1 + x2
+}
+
+// ReadTemperature returns the sensor's die temperature in Celsius.
+func (s *BMP180) ReadTemperature() (float64, error) {
+	rawTemp, err := s.readRawTemp()
+	if err != nil {
+		return 0, err
+	}
+	return float64(s.b5(rawTemp)+8) / 160.0, nil
+}
+
+// ReadPressure returns station pressure in hPa.
+func (s *BMP180) ReadPressure() (float64, error) {
+	rawTemp, err := s.readRawTemp()
+	if err != nil {
+		return 0, err
+	}
+	rawPressure, err := s.readRawPressure()
+	if err != nil {
+		return 0, err
+	}
+
+	b5 := s.b5(rawTemp)
+	b6 := b5 - 4000
+	x1 := (int32(s.b2) * (b6 * b6 / 4096)) / 2048
+	x2 := int32(s.ac2) * b6 / 2048
+	x3 := x1 + x2
+	b3 := (((int32(s.ac1)*4 + x3) << bmp180Oversampling) + 2) / 4
+
+	x1 = int32(s.ac3) * b6 / 8192
+	x2 = (int32(s.b1) * (b6 * b6 / 4096)) / 65536
+	x3 = ((x1 + x2) + 2) / 4
+	b4 := uint32(s.ac4) * uint32(x3+32768) / 32768
+	b7 := (uint32(rawPressure) - uint32(b3)) * (50000 >> bmp180Oversampling)
+
+	var p int32
+	if b7 < 0x80000000 {
+		p = int32((b7 * 2) / b4)
+	} else {
+		p = int32((b7 / b4) * 2)
+	}
+	x1 = (p / 256) * (p / 256)
+	x1 = (x1 * 3038) / 65536
+	x2 = (-7357 * p) / 65536
+	p += (x1 + x2 + 3791) / 16
+
+	// p is in Pa; GoBalloon works in hPa throughout.
+	return float64(p) / 100.0, nil
+}
@@ -0,0 +1,148 @@
+// GoBalloon
+// bmp280.go - Bosch BMP280 I2C driver
+//
+// (c) 2018, Christopher Snell
+
+package baro
+
+import "encoding/binary"
+
+const (
+	bmp280DefaultAddr = 0x76
+	bmp280RegChipID   = 0xD0
+	bmp280ChipID      = 0x58
+	bmp280RegCalib    = 0x88
+	bmp280RegCtrlMeas = 0xF4
+	bmp280RegData     = 0xF7
+)
+
+// BMP280 drives a Bosch BMP280 pressure/temperature sensor.
+type BMP280 struct {
+	bus  Bus
+	addr uint8
+
+	calib bmp280Calibration
+}
+
+type bmp280Calibration struct {
+	t1 uint16
+	t2 int16
+	t3 int16
+	p1 uint16
+	p2 int16
+	p3 int16
+	p4 int16
+	p5 int16
+	p6 int16
+	p7 int16
+	p8 int16
+	p9 int16
+}
+
+// NewBMP280 probes the bus at addr (use bmp280DefaultAddr, 0x76, if 0) and
+// returns a ready-to-use driver, reading the factory calibration
+// coefficients that every conversion depends on.
+func NewBMP280(bus Bus, addr uint8) (*BMP280, error) {
+	if addr == 0 {
+		addr = bmp280DefaultAddr
+	}
+
+	id := make([]byte, 1)
+	if err := bus.ReadReg(addr, bmp280RegChipID, id); err != nil {
+		return nil, err
+	}
+	if id[0] != bmp280ChipID {
+		return nil, ErrNoSensor
+	}
+
+	raw := make([]byte, 24)
+	if err := bus.ReadReg(addr, bmp280RegCalib, raw); err != nil {
+		return nil, err
+	}
+
+	s := &BMP280{bus: bus, addr: addr}
+	s.calib = bmp280Calibration{
+		t1: binary.LittleEndian.Uint16(raw[0:2]),
+		t2: int16(binary.LittleEndian.Uint16(raw[2:4])),
+		t3: int16(binary.LittleEndian.Uint16(raw[4:6])),
+		p1: binary.LittleEndian.Uint16(raw[6:8]),
+		p2: int16(binary.LittleEndian.Uint16(raw[8:10])),
+		p3: int16(binary.LittleEndian.Uint16(raw[10:12])),
+		p4: int16(binary.LittleEndian.Uint16(raw[12:14])),
+		p5: int16(binary.LittleEndian.Uint16(raw[14:16])),
+		p6: int16(binary.LittleEndian.Uint16(raw[16:18])),
+		p7: int16(binary.LittleEndian.Uint16(raw[18:20])),
+		p8: int16(binary.LittleEndian.Uint16(raw[20:22])),
+		p9: int16(binary.LittleEndian.Uint16(raw[22:24])),
+	}
+
+	// Normal mode, 16x pressure oversampling, 2x temperature oversampling.
+	if err := bus.WriteReg(addr, bmp280RegCtrlMeas, []byte{0x57}); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *BMP280) readRaw() (rawTemp, rawPressure int32, err error) {
+	data := make([]byte, 6)
+	if err = s.bus.ReadReg(s.addr, bmp280RegData, data); err != nil {
+		return 0, 0, err
+	}
+	rawPressure = int32(data[0])<<12 | int32(data[1])<<4 | int32(data[2])>>4
+	rawTemp = int32(data[3])<<12 | int32(data[4])<<4 | int32(data[5])>>4
+	return rawTemp, rawPressure, nil
+}
+
+// fineTemperature implements Bosch's reference compensation formula,
+// returning both "t_fine", the fine-resolution value used by the pressure
+// compensation below, and the compensated temperature in Celsius.
+func (s *BMP280) fineTemperature(rawTemp int32) (tFine int32, tempC float64) {
+	c := s.calib
+	v1 := (float64(rawTemp)/16384.0 - float64(c.t1)/1024.0) * float64(c.t2)
+	v2 := (float64(rawTemp)/131072.0 - float64(c.t1)/8192.0) * (float64(rawTemp)/131072.0 - float64(c.t1)/8192.0) * float64(c.t3)
+	tFine = int32(v1 + v2)
+	tempC = (v1 + v2) / 5120.0
+	return tFine, tempC
+}
+
+func (s *BMP280) compensatePressure(rawPressure, tFine int32) float64 {
+	c := s.calib
+	v1 := float64(tFine)/2.0 - 64000.0
+	v2 := v1 * v1 * float64(c.p6) / 32768.0
+	v2 += v1 * float64(c.p5) * 2.0
+	v2 = v2/4.0 + float64(c.p4)*65536.0
+	v1 = (float64(c.p3)*v1*v1/524288.0 + float64(c.p2)*v1) / 524288.0
+	v1 = (1.0 + v1/32768.0) * float64(c.p1)
+	if v1 == 0 {
+		return 0
+	}
+	p := 1048576.0 - float64(rawPressure)
+	p = (p - v2/4096.0) * 6250.0 / v1
+	v1 = float64(c.p9) * p * p / 2147483648.0
+	v2 = p * float64(c.p8) / 32768.0
+	p += (v1 + v2 + float64(c.p7)) / 16.0
+
+	// p is in Pa; GoBalloon works in hPa throughout.
+	return p / 100.0
+}
+
+// ReadPressure returns station pressure in hPa.
+func (s *BMP280) ReadPressure() (float64, error) {
+	rawTemp, rawPressure, err := s.readRaw()
+	if err != nil {
+		return 0, err
+	}
+	tFine, _ := s.fineTemperature(rawTemp)
+	return s.compensatePressure(rawPressure, tFine), nil
+}
+
+// ReadTemperature returns the sensor's die temperature in Celsius.
+func (s *BMP280) ReadTemperature() (float64, error) {
+	rawTemp, _, err := s.readRaw()
+	if err != nil {
+		return 0, err
+	}
+	_, tempC := s.fineTemperature(rawTemp)
+	return tempC, nil
+}
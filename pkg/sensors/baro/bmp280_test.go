@@ -0,0 +1,90 @@
+// GoBalloon
+// bmp280_test.go - tests for the BMP280 driver against Bosch's published
+// double-precision compensation example
+//
+// (c) 2026, GoBalloon contributors
+
+package baro
+
+import (
+	"math"
+	"testing"
+)
+
+func newTestBMP280(t *testing.T) (*BMP280, *fakeBus) {
+	t.Helper()
+
+	bus := &fakeBus{regs: make(map[uint8][]byte)}
+	bus.regs[bmp280RegChipID] = []byte{bmp280ChipID}
+
+	// Bosch's BMP280 datasheet double-precision compensation example.
+	var calib []byte
+	calib = append(calib, u16le(27504)...)  // dig_T1
+	calib = append(calib, i16le(26435)...)  // dig_T2
+	calib = append(calib, i16le(-1000)...)  // dig_T3
+	calib = append(calib, u16le(36477)...)  // dig_P1
+	calib = append(calib, i16le(-10685)...) // dig_P2
+	calib = append(calib, i16le(3024)...)   // dig_P3
+	calib = append(calib, i16le(2855)...)   // dig_P4
+	calib = append(calib, i16le(140)...)    // dig_P5
+	calib = append(calib, i16le(-7)...)     // dig_P6
+	calib = append(calib, i16le(15500)...)  // dig_P7
+	calib = append(calib, i16le(-14600)...) // dig_P8
+	calib = append(calib, i16le(6000)...)   // dig_P9
+	bus.regs[bmp280RegCalib] = calib
+
+	// adc_T=519888, adc_P=415148 from the same worked example, packed as
+	// the 20-bit-in-24-bit big-endian layout readRaw expects: press
+	// bytes then temp bytes, each left-shifted by 4 (xlsb is the low
+	// nibble of the third byte).
+	adcT, adcP := int32(519888), int32(415148)
+	data := make([]byte, 6)
+	data[0] = byte(adcP >> 12)
+	data[1] = byte(adcP >> 4)
+	data[2] = byte(adcP << 4)
+	data[3] = byte(adcT >> 12)
+	data[4] = byte(adcT >> 4)
+	data[5] = byte(adcT << 4)
+	bus.regs[bmp280RegData] = data
+
+	s, err := NewBMP280(bus, 0)
+	if err != nil {
+		t.Fatalf("NewBMP280: %v", err)
+	}
+	return s, bus
+}
+
+func TestBMP280ReadTemperatureMatchesDatasheetExample(t *testing.T) {
+	s, _ := newTestBMP280(t)
+
+	const wantTemp = 25.08247793081682
+	got, err := s.ReadTemperature()
+	if err != nil {
+		t.Fatalf("ReadTemperature: %v", err)
+	}
+	if math.Abs(got-wantTemp) > 1e-9 {
+		t.Errorf("ReadTemperature() = %v, want %v", got, wantTemp)
+	}
+}
+
+func TestBMP280ReadPressureMatchesDatasheetExample(t *testing.T) {
+	s, _ := newTestBMP280(t)
+
+	const wantPressure = 1006.5325814481472
+	got, err := s.ReadPressure()
+	if err != nil {
+		t.Fatalf("ReadPressure: %v", err)
+	}
+	if math.Abs(got-wantPressure) > 1e-9 {
+		t.Errorf("ReadPressure() = %v, want %v", got, wantPressure)
+	}
+}
+
+func TestNewBMP280RejectsWrongChipID(t *testing.T) {
+	bus := &fakeBus{regs: make(map[uint8][]byte)}
+	bus.regs[bmp280RegChipID] = []byte{0x00}
+
+	if _, err := NewBMP280(bus, 0); err != ErrNoSensor {
+		t.Errorf("NewBMP280 with a bad chip ID: err = %v, want ErrNoSensor", err)
+	}
+}
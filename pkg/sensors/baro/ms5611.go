@@ -0,0 +1,122 @@
+// GoBalloon
+// ms5611.go - TE Connectivity MS5611 I2C driver
+//
+// (c) 2018, Christopher Snell
+
+package baro
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+const (
+	ms5611DefaultAddr  = 0x77
+	ms5611CmdReset     = 0x1E
+	ms5611CmdConvertD1 = 0x48 // D1 = pressure, OSR=4096
+	ms5611CmdConvertD2 = 0x58 // D2 = temperature, OSR=4096
+	ms5611CmdADCRead   = 0x00
+	ms5611RegPROMBase  = 0xA0
+)
+
+// MS5611 drives a TE Connectivity MS5611 pressure/temperature sensor.
+// Unlike the BMP180/BMP280, reading a conversion is a two-step command/
+// read sequence rather than a single register read, since the chip
+// doesn't expose continuous measurement mode.
+type MS5611 struct {
+	bus  Bus
+	addr uint8
+
+	coef [6]uint16 // PROM calibration coefficients C1-C6
+}
+
+// NewMS5611 resets the sensor and reads its factory PROM calibration.
+func NewMS5611(bus Bus, addr uint8) (*MS5611, error) {
+	if addr == 0 {
+		addr = ms5611DefaultAddr
+	}
+
+	if err := bus.WriteReg(addr, ms5611CmdReset, nil); err != nil {
+		return nil, err
+	}
+
+	s := &MS5611{bus: bus, addr: addr}
+	// PROM word 0 is reserved/manufacturer data, not a coefficient; C1-C6
+	// are words 1-6.
+	for i := 1; i <= 6; i++ {
+		raw := make([]byte, 2)
+		if err := bus.ReadReg(addr, ms5611RegPROMBase+uint8(i*2), raw); err != nil {
+			return nil, err
+		}
+		s.coef[i-1] = binary.BigEndian.Uint16(raw)
+	}
+
+	return s, nil
+}
+
+func (s *MS5611) convert(cmd uint8) (uint32, error) {
+	if err := s.bus.WriteReg(s.addr, cmd, nil); err != nil {
+		return 0, err
+	}
+	// Wait out the OSR=4096 conversion time (~9ms) before reading back
+	// the ADC result.
+	time.Sleep(10 * time.Millisecond)
+	raw := make([]byte, 3)
+	if err := s.bus.ReadReg(s.addr, ms5611CmdADCRead, raw); err != nil {
+		return 0, err
+	}
+	return uint32(raw[0])<<16 | uint32(raw[1])<<8 | uint32(raw[2]), nil
+}
+
+// readCompensated runs both conversions and applies the datasheet's
+// first-order (and, below 20C, second-order) compensation, returning
+// pressure in hPa and temperature in Celsius.
+func (s *MS5611) readCompensated() (pressureHPa, tempC float64, err error) {
+	d1, err := s.convert(ms5611CmdConvertD1)
+	if err != nil {
+		return 0, 0, err
+	}
+	d2, err := s.convert(ms5611CmdConvertD2)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dT := int64(d2) - int64(s.coef[4])<<8
+	temp := 2000 + (dT*int64(s.coef[5]))>>23
+
+	off := int64(s.coef[1])<<16 + (int64(s.coef[3])*dT)>>7
+	sens := int64(s.coef[0])<<15 + (int64(s.coef[2])*dT)>>8
+
+	if temp < 2000 {
+		// Second-order temperature compensation for low temperatures,
+		// which a HAB payload will spend most of its flight in.
+		t2 := (dT * dT) >> 31
+		tempDelta := temp - 2000
+		off2 := (5 * tempDelta * tempDelta) >> 1
+		sens2 := (5 * tempDelta * tempDelta) >> 2
+		if temp < -1500 {
+			tempDelta2 := temp + 1500
+			off2 += 7 * tempDelta2 * tempDelta2
+			sens2 += 11 * tempDelta2 * tempDelta2 / 2
+		}
+		temp -= t2
+		off -= off2
+		sens -= sens2
+	}
+
+	p := (int64(d1)*sens>>21 - off) >> 15
+
+	return float64(p) / 100.0, float64(temp) / 100.0, nil
+}
+
+// ReadPressure returns station pressure in hPa.
+func (s *MS5611) ReadPressure() (float64, error) {
+	p, _, err := s.readCompensated()
+	return p, err
+}
+
+// ReadTemperature returns the sensor's die temperature in Celsius.
+func (s *MS5611) ReadTemperature() (float64, error) {
+	_, t, err := s.readCompensated()
+	return t, err
+}
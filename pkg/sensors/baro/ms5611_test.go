@@ -0,0 +1,103 @@
+// GoBalloon
+// ms5611_test.go - tests for the MS5611 driver's PROM addressing and
+// datasheet compensation formula
+//
+// (c) 2026, GoBalloon contributors
+
+package baro
+
+import "testing"
+
+// fakePROMBus serves a distinct 16-bit value per PROM command byte
+// (0xA0-0xAC), and distinguishes MS5611's D1/D2 ADC conversions (which
+// share a single read-back command) by which convert command was last
+// written.
+type fakePROMBus struct {
+	prom    map[uint8]uint16
+	d1, d2  uint32
+	lastCmd uint8
+}
+
+func (f *fakePROMBus) ReadReg(addr uint8, reg uint8, p []byte) error {
+	if reg == ms5611CmdADCRead {
+		adc := f.d1
+		if f.lastCmd == ms5611CmdConvertD2 {
+			adc = f.d2
+		}
+		p[0] = byte(adc >> 16)
+		p[1] = byte(adc >> 8)
+		p[2] = byte(adc)
+		return nil
+	}
+	v := f.prom[reg]
+	p[0] = byte(v >> 8)
+	p[1] = byte(v)
+	return nil
+}
+
+func (f *fakePROMBus) WriteReg(addr uint8, reg uint8, p []byte) error {
+	f.lastCmd = reg
+	return nil
+}
+
+func TestNewMS5611ReadsCoefficientsFromWords1Through6(t *testing.T) {
+	bus := &fakePROMBus{prom: map[uint8]uint16{
+		0xA0: 0xFFFF, // reserved/manufacturer word, must never end up in coef
+		0xA2: 1,      // C1
+		0xA4: 2,      // C2
+		0xA6: 3,      // C3
+		0xA8: 4,      // C4
+		0xAA: 5,      // C5
+		0xAC: 6,      // C6
+	}}
+
+	s, err := NewMS5611(bus, 0)
+	if err != nil {
+		t.Fatalf("NewMS5611: %v", err)
+	}
+
+	want := [6]uint16{1, 2, 3, 4, 5, 6}
+	if s.coef != want {
+		t.Errorf("coef = %v, want %v (reserved PROM word 0 must not shift into C1-C6)", s.coef, want)
+	}
+}
+
+func TestMS5611ReadCompensatedMatchesDatasheetExample(t *testing.T) {
+	// C1-C6 and D1/D2 below are TE Connectivity's MS5611-01BA03 datasheet
+	// worked example values; they stay above the threshold for the
+	// low-temperature second-order correction, so only the first-order
+	// formula applies.
+	bus := &fakePROMBus{
+		prom: map[uint8]uint16{
+			0xA2: 40127, // C1
+			0xA4: 36924, // C2
+			0xA6: 23317, // C3
+			0xA8: 23282, // C4
+			0xAA: 33464, // C5
+			0xAC: 28312, // C6
+		},
+		d1: 9085466,
+		d2: 8569150,
+	}
+	s, err := NewMS5611(bus, 0)
+	if err != nil {
+		t.Fatalf("NewMS5611: %v", err)
+	}
+
+	const wantPressureHPa = 1000.09
+	const wantTempC = 20.07
+	gotPressure, err := s.ReadPressure()
+	if err != nil {
+		t.Fatalf("ReadPressure: %v", err)
+	}
+	if gotPressure != wantPressureHPa {
+		t.Errorf("ReadPressure() = %v, want %v", gotPressure, wantPressureHPa)
+	}
+	gotTemp, err := s.ReadTemperature()
+	if err != nil {
+		t.Fatalf("ReadTemperature: %v", err)
+	}
+	if gotTemp != wantTempC {
+		t.Errorf("ReadTemperature() = %v, want %v", gotTemp, wantTempC)
+	}
+}
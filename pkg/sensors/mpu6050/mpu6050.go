@@ -0,0 +1,145 @@
+// GoBalloon
+// mpu6050.go - InvenSense MPU6050 AHRS driver (accelerometer + gyroscope)
+//
+// (c) 2018, Christopher Snell
+
+// Package mpu6050 drives an InvenSense MPU6050 six-axis IMU, computing
+// pitch and roll from the accelerometer and a drift-corrected heading by
+// integrating the gyroscope, optionally fused with a magnetometer.
+package mpu6050
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// Bus is the minimal I2C access that the driver needs.
+type Bus interface {
+	ReadReg(addr uint8, reg uint8, p []byte) error
+	WriteReg(addr uint8, reg uint8, p []byte) error
+}
+
+// Magnetometer is implemented by an auxiliary compass (e.g. an HMC5883L
+// or QMC5883L) used to correct gyro heading drift. It is optional: a
+// Sensor constructed without one falls back to pure gyro integration.
+type Magnetometer interface {
+	// ReadHeading returns compass heading in degrees, 0-360, true north.
+	ReadHeading() (float64, error)
+}
+
+const (
+	defaultAddr = 0x68
+
+	regPwrMgmt1  = 0x6B
+	regAccelXOut = 0x3B
+	regGyroXOut  = 0x43
+
+	accelScale = 16384.0 // LSB/g at default +-2g range
+	gyroScale  = 131.0   // LSB/(deg/s) at default +-250deg/s range
+
+	// magWeight is how strongly each update pulls the integrated
+	// heading toward the magnetometer's reading, rather than trusting
+	// the gyro integration outright. A complementary filter, not a
+	// full Kalman filter, is plenty for a slowly-rotating balloon
+	// payload.
+	magWeight = 0.02
+)
+
+// Sensor drives an MPU6050, tracking orientation across successive
+// Update calls.
+type Sensor struct {
+	bus  Bus
+	addr uint8
+	mag  Magnetometer
+
+	heading  float64
+	lastRead time.Time
+}
+
+// New probes the bus at addr (use defaultAddr, 0x68, if 0), wakes the
+// sensor from sleep mode, and returns a ready-to-use driver. mag may be
+// nil if no magnetometer is present; heading will then be gyro-only and
+// will drift over a long flight.
+func New(bus Bus, addr uint8, mag Magnetometer) (*Sensor, error) {
+	if addr == 0 {
+		addr = defaultAddr
+	}
+
+	// Clear the sleep bit to start sampling.
+	if err := bus.WriteReg(addr, regPwrMgmt1, []byte{0x00}); err != nil {
+		return nil, err
+	}
+
+	return &Sensor{bus: bus, addr: addr, mag: mag}, nil
+}
+
+// Orientation is one AHRS reading.
+type Orientation struct {
+	// Pitch and Roll are in degrees, derived purely from the
+	// accelerometer so they carry no long-term drift.
+	Pitch float64
+	Roll  float64
+
+	// Heading is in degrees, 0-360 true north, from gyro integration
+	// with magnetometer drift correction when available.
+	Heading float64
+}
+
+// Update reads the sensor, integrates the gyroscope since the last call,
+// and returns the resulting orientation. The first call after New
+// establishes the time base and returns a heading of 0 (or the
+// magnetometer's reading, if present).
+func (s *Sensor) Update() (Orientation, error) {
+	data := make([]byte, 6)
+	if err := s.bus.ReadReg(s.addr, regAccelXOut, data); err != nil {
+		return Orientation{}, err
+	}
+	ax := float64(int16(binary.BigEndian.Uint16(data[0:2]))) / accelScale
+	ay := float64(int16(binary.BigEndian.Uint16(data[2:4]))) / accelScale
+	az := float64(int16(binary.BigEndian.Uint16(data[4:6]))) / accelScale
+
+	pitch := math.Atan2(-ax, math.Sqrt(ay*ay+az*az)) * 180 / math.Pi
+	roll := math.Atan2(ay, az) * 180 / math.Pi
+
+	if err := s.bus.ReadReg(s.addr, regGyroXOut, data); err != nil {
+		return Orientation{}, err
+	}
+	gz := float64(int16(binary.BigEndian.Uint16(data[4:6]))) / gyroScale
+
+	now := time.Now()
+	first := s.lastRead.IsZero()
+	if !first {
+		dt := now.Sub(s.lastRead).Seconds()
+		s.heading = normalizeHeading(s.heading + gz*dt)
+	}
+	s.lastRead = now
+
+	if s.mag != nil {
+		if magHeading, err := s.mag.ReadHeading(); err == nil {
+			if first {
+				s.heading = normalizeHeading(magHeading)
+			} else {
+				s.heading = normalizeHeading(complementaryBlend(s.heading, magHeading, magWeight))
+			}
+		}
+	}
+
+	return Orientation{Pitch: pitch, Roll: roll, Heading: s.heading}, nil
+}
+
+func normalizeHeading(h float64) float64 {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// complementaryBlend nudges gyroHeading toward magHeading by weight,
+// taking the shortest angular path so that a crossing of the 0/360
+// boundary doesn't cause a 360-degree snap.
+func complementaryBlend(gyroHeading, magHeading, weight float64) float64 {
+	diff := math.Mod(magHeading-gyroHeading+540, 360) - 180
+	return gyroHeading + diff*weight
+}
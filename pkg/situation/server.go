@@ -0,0 +1,48 @@
+// GoBalloon
+// server.go - Embedded HTTP server exposing /getSituation
+//
+// (c) 2018, Christopher Snell
+
+package situation
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// DefaultAddr is the address the situation server listens on unless the
+// caller configures a different one.
+const DefaultAddr = ":8080"
+
+// Server serves the current Tracker snapshot as JSON.
+type Server struct {
+	tracker *Tracker
+	http    *http.Server
+}
+
+// NewServer returns a Server that will serve t's snapshot from addr
+// (e.g. ":8080") once Start is called. An empty addr uses DefaultAddr.
+func NewServer(addr string, t *Tracker) *Server {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+
+	mux := http.NewServeMux()
+	s := &Server{tracker: t}
+	mux.HandleFunc("/getSituation", s.handleGetSituation)
+	s.http = &http.Server{Addr: addr, Handler: mux}
+
+	return s
+}
+
+// Start listens and serves until the process exits or ListenAndServe
+// returns an error; it blocks, so callers typically run it in its own
+// goroutine.
+func (s *Server) Start() error {
+	return s.http.ListenAndServe()
+}
+
+func (s *Server) handleGetSituation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.tracker.Snapshot())
+}
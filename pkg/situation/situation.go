@@ -0,0 +1,106 @@
+// GoBalloon
+// situation.go - In-memory tracking of decoded station positions
+//
+// (c) 2018, Christopher Snell
+
+// Package situation keeps track of the most recently decoded position
+// for every callsign GoBalloon has heard, along with basic receiver
+// statistics, and serves them as JSON in a shape compatible with
+// existing Stratux-style web UIs.
+package situation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/chrissnell/GoBalloon/pkg/geospatial"
+)
+
+// Station is the last known situation for one callsign.
+type Station struct {
+	Callsign    string    `json:"callsign"`
+	Lat         float64   `json:"lat"`
+	Lon         float64   `json:"lon"`
+	Altitude    float64   `json:"altitude"`
+	Speed       float64   `json:"speed"`
+	Course      float64   `json:"course"`
+	RadioRange  float64   `json:"radio_range"`
+	SymbolTable string    `json:"symbol_table"`
+	SymbolCode  string    `json:"symbol_code"`
+	LastHeard   time.Time `json:"last_heard"`
+}
+
+// ReceiverStats summarizes the receiver's activity since it started.
+type ReceiverStats struct {
+	FramesReceived uint64    `json:"frames_received"`
+	FramesSent     uint64    `json:"frames_sent"`
+	StartedAt      time.Time `json:"started_at"`
+}
+
+// Tracker is a concurrency-safe store of the most recent Station for
+// each callsign heard, plus overall ReceiverStats.
+type Tracker struct {
+	mu       sync.RWMutex
+	stations map[string]Station
+	stats    ReceiverStats
+}
+
+// NewTracker returns an empty Tracker with its start time set to now.
+func NewTracker() *Tracker {
+	return &Tracker{
+		stations: make(map[string]Station),
+		stats:    ReceiverStats{StartedAt: time.Now()},
+	}
+}
+
+// Update records a freshly decoded position report for callsign.
+func (t *Tracker) Update(callsign string, symTable, symCode rune, p geospatial.Point) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stations[callsign] = Station{
+		Callsign:    callsign,
+		Lat:         p.Lat,
+		Lon:         p.Lon,
+		Altitude:    p.Altitude,
+		Speed:       p.Speed,
+		Course:      p.Heading,
+		RadioRange:  p.RadioRange,
+		SymbolTable: string(symTable),
+		SymbolCode:  string(symCode),
+		LastHeard:   time.Now(),
+	}
+}
+
+// IncrementFramesReceived records one more AX.25 frame decoded off the air.
+func (t *Tracker) IncrementFramesReceived() {
+	t.mu.Lock()
+	t.stats.FramesReceived++
+	t.mu.Unlock()
+}
+
+// IncrementFramesSent records one more AX.25 frame handed to EncodeAX25Command.
+func (t *Tracker) IncrementFramesSent() {
+	t.mu.Lock()
+	t.stats.FramesSent++
+	t.mu.Unlock()
+}
+
+// Situation is the full JSON shape served by /getSituation.
+type Situation struct {
+	Stations []Station     `json:"stations"`
+	Receiver ReceiverStats `json:"receiver"`
+}
+
+// Snapshot returns the current situation across all known stations.
+func (t *Tracker) Snapshot() Situation {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stations := make([]Station, 0, len(t.stations))
+	for _, s := range t.stations {
+		stations = append(stations, s)
+	}
+
+	return Situation{Stations: stations, Receiver: t.stats}
+}